@@ -3,29 +3,85 @@ package main
 import (
 	// project
 	"go-url-shortener/internal/config"
+	keyCreate "go-url-shortener/internal/http-server/handlers/apikey/create"
+	keyList "go-url-shortener/internal/http-server/handlers/apikey/list"
+	keyRevoke "go-url-shortener/internal/http-server/handlers/apikey/revoke"
+	"go-url-shortener/internal/http-server/middleware/auth"
+	"go-url-shortener/internal/http-server/middleware/ratelimit"
 	"go-url-shortener/internal/http-server/handlers/save"
 	mwLogger "go-url-shortener/internal/http-server/middleware/logger"
 	"go-url-shortener/internal/lib/logger/handlers/slogpretty"
+	"go-url-shortener/internal/lib/alias"
 	"go-url-shortener/internal/lib/logger/sl"
+	"go-url-shortener/internal/lib/random"
+	"go-url-shortener/internal/lib/urlnorm"
+	"go-url-shortener/internal/storage/memory"
 	"go-url-shortener/internal/storage/postgres"
+	"go-url-shortener/internal/storage/reaper"
+	"go-url-shortener/internal/storage/sqlite"
 	"go-url-shortener/internal/http-server/handlers/redirect"
 	"go-url-shortener/internal/http-server/handlers/delete"
+	"go-url-shortener/internal/http-server/handlers/info"
+	"go-url-shortener/internal/http-server/handlers/list"
+	"go-url-shortener/internal/http-server/handlers/stats"
+	"go-url-shortener/internal/http-server/handlers/update"
+	"go-url-shortener/internal/http-server/handlers/health/live"
+	"go-url-shortener/internal/http-server/handlers/health/ready"
+	"go-url-shortener/internal/storage/clicks"
+	"go-url-shortener/internal/storage/cache"
+	"go-url-shortener/internal/storage/metrics"
+	mwMetrics "go-url-shortener/internal/http-server/middleware/metrics"
+	mwTracing "go-url-shortener/internal/http-server/middleware/tracing"
 
 	// embedded
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"os/signal"
 	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	// external
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-const (
-	envLocal = "local"
-	envDev   = "dev"
-	envProd  = "prod"
-)
+// Storage is the set of operations the http handlers need from a backend.
+type Storage interface {
+	save.URLSaver
+	save.URLFinder
+	redirect.URLGetter
+	cache.URLGetter
+	delete.URLDeleter
+	update.URLUpdater
+	list.URLLister
+	info.URLInfoGetter
+	reaper.ExpiredURLDeleter
+	clicks.ClickSaver
+	stats.StatsGetter
+	auth.APIKeyGetter
+	keyCreate.APIKeySaver
+	keyList.APIKeyLister
+	keyRevoke.APIKeyRevoker
+	alias.Sequence
+	ready.Pinger
+	io.Closer
+}
+
+// invalidationListener is implemented by backends shared between instances.
+type invalidationListener interface {
+	ListenInvalidations(ctx context.Context, log *slog.Logger, invalidator postgres.Invalidator) error
+}
 
 func main() {
 	// init config: cleanenv
@@ -36,32 +92,157 @@ func main() {
 	log.Info("starting url-shortener", slog.String("env", cfg.Env))
 	log.Debug("debug messages are enabled")
 
-	// init storage: postgress
-	storage, err := postgres.NewStorage(cfg.Postgres)
+	// init tracing: opentelemetry, before the storage instruments its queries
+	shutdownTracing, err := setupTracing(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Error("failed to init tracing", sl.Err(err))
+		os.Exit(1)
+	}
+
+	// init storage: postgres, sqlite or memory
+	storage, err := setupStorage(cfg)
 	if err != nil {
 		log.Error("failed to init storage", sl.Err(err))
 		os.Exit(1)
 	}
-	_ = storage
+	log.Info("storage initialized", slog.String("driver", cfg.Storage.Driver))
+
+	// migrate subcommand: url-shortener [--config path] migrate [up|down [n]|version]
+	if args := flag.Args(); len(args) > 0 && args[0] == "migrate" {
+		err := runMigrate(log, storage, args[1:])
+		_ = storage.Close()
+		if err != nil {
+			log.Error("migration failed", sl.Err(err))
+			os.Exit(1)
+		}
+		return
+	}
+
+	if _, ok := storage.(migratable); ok && cfg.Storage.AutoMigrate {
+		if err := runMigrate(log, storage, []string{"up"}); err != nil {
+			log.Error("failed to migrate storage", sl.Err(err))
+			os.Exit(1)
+		}
+	}
+
+	// stop on SIGINT/SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+
+	// purge expired urls in background
+	var background sync.WaitGroup
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		reaper.Run(ctx, log, storage, cfg.Storage.ReaperInterval)
+	}()
+
+	// record clicks asynchronously
+	clickRecorder := clicks.NewRecorder(log, storage,
+		cfg.Clicks.BufferSize, cfg.Clicks.BatchSize, cfg.Clicks.FlushInterval,
+	)
+
+	// cache redirect lookups, writes through the cache invalidate them
+	var (
+		urlGetter  redirect.URLGetter = storage
+		urlSaver   save.URLSaver      = storage
+		urlUpdater update.URLUpdater  = storage
+		urlDeleter delete.URLDeleter  = storage
+		urlCache   *cache.Cache
+	)
+	if cfg.Cache.Size > 0 {
+		urlCache = cache.New(storage, cfg.Cache.Size, cfg.Cache.TTL, cfg.Cache.NegativeTTL)
+		urlGetter = urlCache
+		urlSaver = urlCache.Saver(storage)
+		urlUpdater = urlCache.Updater(storage)
+		urlDeleter = urlCache.Deleter(storage)
+
+		// other instances change urls too, postgres tells about it
+		if l, ok := storage.(invalidationListener); ok {
+			background.Add(1)
+			go func() {
+				defer background.Done()
+				if err := l.ListenInvalidations(ctx, log, urlCache); err != nil {
+					log.Error("failed to listen for cache invalidations", sl.Err(err))
+				}
+			}()
+		}
+	}
+
+	// init metrics: prometheus, served by the admin server
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	links := metrics.NewLinks(registry)
+	urlSaver = links.Saver(urlSaver)
+	urlDeleter = links.Deleter(urlDeleter)
+	if urlCache != nil {
+		metrics.RegisterCache(registry, urlCache)
+	}
+	if db, ok := storage.(metrics.DBStatser); ok {
+		metrics.RegisterDB(registry, cfg.Storage.Driver, db)
+	}
 
 	// init router: chi, "chi render"
 	router := chi.NewRouter()
 	// middleware
 	router.Use(middleware.RequestID)
-	router.Use(mwLogger.New(log))
+	router.Use(mwTracing.New(log))
+	router.Use(mwLogger.New(log, "/healthz", "/readyz"))
+	router.Use(mwMetrics.New(log, registry))
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.URLFormat)
-	// autherization
+	// custom aliases must not shadow routes, they are added once all are registered
+	reservedAliases := alias.NewReserved(cfg.Aliases.Reserved...)
+	normalize := urlnorm.Options{SortQuery: cfg.URLs.SortQuery}
+	saveOpts := save.Options{
+		Generator:       setupAliasGenerator(cfg.Aliases, storage),
+		MaxCustomLength: cfg.Aliases.MaxCustomLength,
+		Reserved:        reservedAliases,
+		Normalize:       normalize,
+	}
+	if cfg.Aliases.Dedupe {
+		saveOpts.Dedupe = storage
+	}
+	// probes for the orchestrator, public and not logged
+	router.Get("/healthz", live.New())
+	router.Get("/readyz", ready.New(log, storage, cfg.Health.ReadyTimeout, ctx.Done()))
+	// autherization: Authorization: Bearer <api key>
+	// failed authentications are limited per ip before the key is looked up
+	authLimit := ratelimit.FailedAuth(log, cfg.RateLimit.AuthFailRPS, cfg.RateLimit.AuthFailBurst)
+	authMiddleware := auth.New(log, storage, cfg.Auth.AdminKey)
 	router.Route("/url", func(r chi.Router) {
-		r.Use(middleware.BasicAuth("url-shortener", map[string]string{
-			cfg.HttpServer.User: cfg.HttpServer.Password,
-		}))	
+		r.Use(authLimit)
+		r.Use(authMiddleware)
+		r.Use(ratelimit.New(log, "url", cfg.RateLimit.URLRPS, cfg.RateLimit.URLBurst))
+
+		r.Get("/", list.New(log, storage))
+		r.Post("/", save.New(log, urlSaver, saveOpts))
+		r.Get("/{alias}", info.New(log, storage))
+		r.Put("/{alias}", update.New(log, urlUpdater, normalize))
+		r.Patch("/{alias}", update.New(log, urlUpdater, normalize))
+		r.Delete("/{alias}", delete.New(log, urlDeleter))
+		r.Get("/{alias}/stats", stats.New(log, storage))
+	})
+	router.Route("/admin/keys", func(r chi.Router) {
+		r.Use(authLimit)
+		r.Use(authMiddleware)
+		r.Use(auth.RequireAdmin)
 
-		r.Post("/", save.New(log, storage))
-		r.Delete("/{alias}", delete.New(log, storage))
+		r.Get("/", keyList.New(log, storage))
+		r.Post("/", keyCreate.New(log, storage))
+		r.Delete("/{id}", keyRevoke.New(log, storage))
 	})
-	// handlers
-	router.Get("/{alias}", redirect.New(log, storage)) 
+	// public redirect, limited per client ip
+	router.With(
+		ratelimit.New(log, "redirect", cfg.RateLimit.RedirectRPS, cfg.RateLimit.RedirectBurst),
+	).Get("/{alias}", redirect.New(log, urlGetter, clickRecorder))
+
+	if err := reserveRoutes(router, reservedAliases); err != nil {
+		log.Error("failed to reserve routes", sl.Err(err))
+		os.Exit(1)
+	}
 
 	// start server
 	log.Info("starting server", slog.String("address", cfg.HttpServer.Address))
@@ -72,26 +253,140 @@ func main() {
 		WriteTimeout: cfg.HttpServer.Timeout,
 		IdleTimeout:  cfg.HttpServer.IdleTimeout,
 	}
-	
-	if err := srv.ListenAndServe(); err != nil {
-		log.Error("failed to stop server")
-		return
+
+	serverErr := make(chan error, 2)
+	go func() {
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	var adminSrv *http.Server
+	if cfg.Admin.Address != "" {
+		log.Info("starting admin server", slog.String("address", cfg.Admin.Address))
+		adminRouter := chi.NewRouter()
+		adminRouter.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		adminSrv = &http.Server{
+			Addr:         cfg.Admin.Address,
+			Handler:      adminRouter,
+			ReadTimeout:  cfg.HttpServer.Timeout,
+			WriteTimeout: cfg.HttpServer.Timeout,
+			IdleTimeout:  cfg.HttpServer.IdleTimeout,
+		}
+		go func() {
+			serverErr <- adminSrv.ListenAndServe()
+		}()
+	}
+
+	exitCode := 0
+	select {
+	case <-ctx.Done():
+		log.Info("shutdown signal received")
+	case err := <-serverErr:
+		log.Error("server failed", sl.Err(err))
+		exitCode = 1
+	}
+	stop()
+
+	// keep serving while /readyz reports the shutdown
+	if cfg.HttpServer.ShutdownDelay > 0 && exitCode == 0 {
+		log.Info("waiting before shutdown", slog.String("delay", cfg.HttpServer.ShutdownDelay.String()))
+		time.Sleep(cfg.HttpServer.ShutdownDelay)
 	}
 
+	// drain in-flight requests, then flush pending work and close storage
+	log.Info("stopping server", slog.String("timeout", cfg.HttpServer.ShutdownTimeout.String()))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HttpServer.ShutdownTimeout)
+	if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error("failed to stop server gracefully", sl.Err(err))
+		exitCode = 1
+	}
+	if adminSrv != nil {
+		if err := adminSrv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("failed to stop admin server gracefully", sl.Err(err))
+			exitCode = 1
+		}
+	}
+	cancel()
+
+	clickRecorder.Close()
+	background.Wait()
+
+	if urlCache != nil {
+		stats := urlCache.Stats()
+		log.Info("url cache stats",
+			slog.Int64("hits", stats.Hits),
+			slog.Int64("misses", stats.Misses),
+			slog.Int("size", stats.Size),
+		)
+	}
+
+	if err := storage.Close(); err != nil {
+		log.Error("failed to close storage", sl.Err(err))
+		exitCode = 1
+	}
+
+	// export the spans of the last requests
+	flushCtx, cancel := context.WithTimeout(context.Background(), cfg.HttpServer.ShutdownTimeout)
+	if err := shutdownTracing(flushCtx); err != nil {
+		log.Error("failed to flush traces", sl.Err(err))
+		exitCode = 1
+	}
+	cancel()
+
 	log.Info("server stopped")
+	os.Exit(exitCode)
+}
+
+func setupStorage(cfg *config.Config) (Storage, error) {
+	switch cfg.Storage.Driver {
+	case config.DriverPostgres:
+		return postgres.NewStorage(cfg.Postgres)
+	case config.DriverSQLite:
+		return sqlite.NewStorage(cfg.SQLite.StoragePath)
+	case config.DriverMemory:
+		return memory.NewStorage(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
+
+// reserveRoutes reserves the first path segment of every route, "url" for
+// "/url/{alias}" and so on. Segments that are route params are skipped.
+func reserveRoutes(router chi.Routes, reserved *alias.Reserved) error {
+	return chi.Walk(router, func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		segment, _, _ := strings.Cut(strings.TrimPrefix(route, "/"), "/")
+		if !strings.HasPrefix(segment, "{") {
+			reserved.Add(segment)
+		}
+		return nil
+	})
+}
+
+func setupAliasGenerator(cfg config.AliasesConfig, seq alias.Sequence) save.AliasGenerator {
+	if cfg.Strategy == config.StrategyCounter {
+		return alias.NewCounter(seq, cfg.CounterKey)
+	}
+
+	switch cfg.Alphabet {
+	case config.AlphabetLowercase:
+		return alias.NewRandom(cfg.Length, random.Lowercase)
+	case config.AlphabetUnambiguous:
+		return alias.NewRandom(cfg.Length, random.Unambiguous)
+	default:
+		return alias.NewRandom(cfg.Length, random.Base62)
+	}
 }
 
 func setupLogger(env string) *slog.Logger {
 	var log *slog.Logger
 
 	switch env {
-	case envLocal:
+	case config.EnvLocal:
 		log = setupPrettySlog()
-	case envDev:
+	case config.EnvDev:
 		log = slog.New(
 			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
 		)
-	case envProd:
+	case config.EnvProd:
 		log = slog.New(
 			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}),
 		)
@@ -0,0 +1,73 @@
+package main
+
+import (
+	// project
+	"go-url-shortener/internal/storage/migrate"
+
+	// embedded
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+var errNoMigrations = errors.New("storage driver has no schema migrations")
+
+// migratable is implemented by the sql backends, the memory one has no schema.
+type migratable interface {
+	Migrator() (*migrate.Migrator, error)
+}
+
+func runMigrate(log *slog.Logger, storage Storage, args []string) error {
+	ms, ok := storage.(migratable)
+	if !ok {
+		return errNoMigrations
+	}
+
+	m, err := ms.Migrator()
+	if err != nil {
+		return err
+	}
+
+	cmd := "up"
+	if len(args) > 0 {
+		cmd = args[0]
+	}
+
+	switch cmd {
+	case "up":
+		applied, err := m.Up()
+		for _, mg := range applied {
+			log.Info("migration applied", slog.Int64("version", mg.Version), slog.String("name", mg.Name))
+		}
+		if err != nil {
+			return err
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		reverted, err := m.Down(steps)
+		for _, mg := range reverted {
+			log.Info("migration reverted", slog.Int64("version", mg.Version), slog.String("name", mg.Name))
+		}
+		if err != nil {
+			return err
+		}
+	case "version":
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or version", cmd)
+	}
+
+	version, err := m.Version()
+	if err != nil {
+		return err
+	}
+	log.Info("schema version", slog.Int64("version", version))
+
+	return nil
+}
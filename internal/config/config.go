@@ -2,53 +2,306 @@ package config
 
 import (
 	// embedded
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"slices"
 	"time"
 
 	// external
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
+const defaultConfigPath = "./config/local.yaml"
+
+const (
+	EnvLocal = "local"
+	EnvDev   = "dev"
+	EnvProd  = "prod"
+)
+
+const (
+	StrategyRandom  = "random"
+	StrategyCounter = "counter"
+)
+
+const (
+	AlphabetBase62      = "base62"
+	AlphabetLowercase   = "lowercase"
+	AlphabetUnambiguous = "unambiguous"
+)
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	DriverMemory   = "memory"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
 type Config struct {
-	Env        string           `yaml:"env" env-default:"local"`
-	Postgres  PostgresConfig  `yaml:"postgres"`
+	Env        string           `yaml:"env" env:"ENV" env-default:"local"`
+	Storage    StorageConfig    `yaml:"storage"`
+	Postgres   PostgresConfig   `yaml:"postgres"`
+	SQLite     SQLiteConfig     `yaml:"sqlite"`
 	HttpServer HttpServerConfig `yaml:"http_server"`
+	Admin      AdminConfig      `yaml:"admin_server"`
+	Health     HealthConfig     `yaml:"health"`
+	Tracing    TracingConfig    `yaml:"tracing"`
+	Clicks     ClicksConfig     `yaml:"clicks"`
+	Auth       AuthConfig       `yaml:"auth"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
+	Aliases    AliasesConfig    `yaml:"aliases"`
+	URLs       URLsConfig       `yaml:"urls"`
+	Cache      CacheConfig      `yaml:"cache"`
+}
+
+// StorageConfig selects the storage backend: "postgres", "sqlite" or "memory".
+// AutoMigrate applies pending schema migrations on startup.
+// ReaperInterval is how often expired urls are purged.
+type StorageConfig struct {
+	Driver         string        `yaml:"driver" env:"STORAGE_DRIVER" env-default:"postgres"`
+	AutoMigrate    bool          `yaml:"auto_migrate" env:"STORAGE_AUTO_MIGRATE" env-default:"true"`
+	ReaperInterval time.Duration `yaml:"reaper_interval" env:"STORAGE_REAPER_INTERVAL" env-default:"1m"`
 }
 
 type PostgresConfig struct {
-	Host     string `yaml:"host" env-default:"localhost"`
-	Port     string `yaml:"port" env-default:"5432"`
-	User     string `yaml:"user" env-default:"postgres"`
-	Password string `yaml:"password" env-default:"password"`
-	DBName   string `yaml:"dbname" env-default:"url_shortener"`
+	Host     string `yaml:"host" env:"POSTGRES_HOST" env-default:"localhost"`
+	Port     string `yaml:"port" env:"POSTGRES_PORT" env-default:"5432"`
+	User     string `yaml:"user" env:"POSTGRES_USER" env-default:"postgres"`
+	Password string `yaml:"password" env:"POSTGRES_PASSWORD" env-default:"password"`
+	DBName   string `yaml:"dbname" env:"POSTGRES_DBNAME" env-default:"url_shortener"`
+}
+
+type SQLiteConfig struct {
+	StoragePath string `yaml:"storage_path" env:"SQLITE_STORAGE_PATH" env-default:"./storage/storage.db"`
+}
+
+// ClicksConfig tunes the async click recorder: clicks are buffered up to
+// BufferSize and written in batches of BatchSize at least every FlushInterval.
+type ClicksConfig struct {
+	BufferSize    int           `yaml:"buffer_size" env:"CLICKS_BUFFER_SIZE" env-default:"4096"`
+	BatchSize     int           `yaml:"batch_size" env:"CLICKS_BATCH_SIZE" env-default:"100"`
+	FlushInterval time.Duration `yaml:"flush_interval" env:"CLICKS_FLUSH_INTERVAL" env-default:"1s"`
 }
 
+// ShutdownDelay is how long the server keeps serving, reporting not ready,
+// after the shutdown signal, so that load balancers stop sending traffic.
+// ShutdownTimeout bounds how long in-flight requests are drained on stop.
 type HttpServerConfig struct {
-	Address     string        `yaml:"address" env-default:":8082"`
-	Timeout     time.Duration `yaml:"timeout" env-default:"4s"`
-	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
-	User		string        `yaml:"user" env-required:"true"`
-	Password	string        `yaml:"password" env-required:"true" env:"HTTP_SERVER_PASSWORD"`
+	Address         string        `yaml:"address" env:"HTTP_SERVER_ADDRESS" env-default:":8082"`
+	Timeout         time.Duration `yaml:"timeout" env:"HTTP_SERVER_TIMEOUT" env-default:"4s"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" env:"HTTP_SERVER_IDLE_TIMEOUT" env-default:"60s"`
+	ShutdownDelay   time.Duration `yaml:"shutdown_delay" env:"HTTP_SERVER_SHUTDOWN_DELAY" env-default:"0s"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"HTTP_SERVER_SHUTDOWN_TIMEOUT" env-default:"10s"`
 }
 
-func MustLoad() *Config {
-	configPath := "D:/GoStudy/go-url-shortener/config/local.yaml" 
-	if err := os.Setenv("CONFIG_PATH", configPath); err != nil {
-		log.Fatal("CONFIG_PATH is not set")
-		return nil
-	}
+// AdminConfig is the listener of operational endpoints such as /metrics,
+// kept apart from the public one. An empty Address disables it.
+type AdminConfig struct {
+	Address string `yaml:"address" env:"ADMIN_SERVER_ADDRESS" env-default:"localhost:8083"`
+}
+
+// HealthConfig bounds how long /readyz waits for the storage ping.
+type HealthConfig struct {
+	ReadyTimeout time.Duration `yaml:"ready_timeout" env:"HEALTH_READY_TIMEOUT" env-default:"1s"`
+}
+
+// TracingConfig selects where OpenTelemetry spans go: "none" disables
+// tracing, "stdout" prints them and "otlp" sends them over OTLP/HTTP to
+// Endpoint. SampleRatio is the share of new traces that are recorded,
+// requests continuing a trace follow the caller's decision.
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER" env-default:"none"`
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT" env-default:"http://localhost:4318"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+}
+
+// AuthConfig holds the bootstrap admin key. It is accepted with the admin
+// role without being stored and is meant for creating the first api keys.
+type AuthConfig struct {
+	AdminKey string `yaml:"admin_key" env:"AUTH_ADMIN_KEY"`
+}
+
+// RateLimitConfig limits requests per client, separately for the /url
+// management routes and the public redirect. Failed authentications are
+// limited per IP before the api key is looked up. Tokens are refilled at
+// RPS per second up to Burst, a zero RPS disables the limit.
+type RateLimitConfig struct {
+	URLRPS        float64 `yaml:"url_rps" env:"RATE_LIMIT_URL_RPS" env-default:"5"`
+	URLBurst      int     `yaml:"url_burst" env:"RATE_LIMIT_URL_BURST" env-default:"20"`
+	RedirectRPS   float64 `yaml:"redirect_rps" env:"RATE_LIMIT_REDIRECT_RPS" env-default:"50"`
+	RedirectBurst int     `yaml:"redirect_burst" env:"RATE_LIMIT_REDIRECT_BURST" env-default:"100"`
+	AuthFailRPS   float64 `yaml:"auth_fail_rps" env:"RATE_LIMIT_AUTH_FAIL_RPS" env-default:"0.2"`
+	AuthFailBurst int     `yaml:"auth_fail_burst" env:"RATE_LIMIT_AUTH_FAIL_BURST" env-default:"10"`
+}
+
+// AliasesConfig shapes aliases. Strategy "random" generates Length
+// characters from the Alphabet preset ("base62", "lowercase" or
+// "unambiguous", the latter without 0, O, 1 and l). Strategy "counter"
+// encodes a database sequence to base62, obfuscated with CounterKey unless
+// it is zero. Custom aliases may be up to MaxCustomLength long and must not
+// be one of Reserved, nor the first segment of any registered route.
+// Dedupe returns the existing alias when an owner shortens the same url
+// again without asking for a custom alias or an expiry.
+type AliasesConfig struct {
+	Strategy        string   `yaml:"strategy" env:"ALIASES_STRATEGY" env-default:"random"`
+	Length          int      `yaml:"length" env:"ALIASES_LENGTH" env-default:"6"`
+	Alphabet        string   `yaml:"alphabet" env:"ALIASES_ALPHABET" env-default:"base62"`
+	CounterKey      uint64   `yaml:"counter_key" env:"ALIASES_COUNTER_KEY"`
+	MaxCustomLength int      `yaml:"max_custom_length" env:"ALIASES_MAX_CUSTOM_LENGTH" env-default:"32"`
+	Reserved        []string `yaml:"reserved" env:"ALIASES_RESERVED" env-default:"admin,api,health,healthz,readyz,metrics,static"`
+	Dedupe          bool     `yaml:"dedupe" env:"ALIASES_DEDUPE"`
+}
+
+// URLsConfig tunes url normalization. SortQuery orders query params by
+// key, off by default as some servers depend on their order.
+type URLsConfig struct {
+	SortQuery bool `yaml:"sort_query" env:"URLS_SORT_QUERY"`
+}
 
+// CacheConfig sizes the redirect lookup cache, a zero Size disables it.
+// Found urls are kept for TTL, but not past their expiry, unknown aliases
+// for NegativeTTL.
+type CacheConfig struct {
+	Size        int           `yaml:"size" env:"CACHE_SIZE" env-default:"10000"`
+	TTL         time.Duration `yaml:"ttl" env:"CACHE_TTL" env-default:"1m"`
+	NegativeTTL time.Duration `yaml:"negative_ttl" env:"CACHE_NEGATIVE_TTL" env-default:"5s"`
+}
 
-	// check if file exists
+// MustLoad resolves the config path from the --config flag, then the
+// CONFIG_PATH env var, then the default path, and exits if the config
+// can't be loaded or is invalid.
+func MustLoad() *Config {
+	configPath, explicit := fetchConfigPath()
+
+	// an explicitly requested file must exist, the default one may be
+	// missing and then the config comes from the environment only
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		log.Fatalf("config file %s does not exist", configPath)
+		if explicit {
+			log.Fatalf("config file %s does not exist", configPath)
+		}
+		configPath = ""
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		log.Fatalf("failed to load config: %s", err)
 	}
 
+	return cfg
+}
+
+// Load reads the config from configPath, or from the environment only when
+// configPath is empty, and validates it.
+func Load(configPath string) (*Config, error) {
 	var cfg Config
-	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
-		log.Fatalf("failed to read config: %s", err)
+
+	if configPath != "" {
+		if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to read config %s: %w", configPath, err)
+		}
+	} else {
+		if err := cleanenv.ReadEnv(&cfg); err != nil {
+			return nil, fmt.Errorf("failed to read config from env: %w", err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
+	}
+
+	return &cfg, nil
+}
+
+// Validate reports every invalid field at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(slices.Contains([]string{EnvLocal, EnvDev, EnvProd}, c.Env),
+		"env: must be one of local, dev, prod, got %q", c.Env)
+
+	check(slices.Contains([]string{DriverPostgres, DriverSQLite, DriverMemory}, c.Storage.Driver),
+		"storage.driver: must be one of postgres, sqlite, memory, got %q", c.Storage.Driver)
+	check(c.Storage.ReaperInterval > 0, "storage.reaper_interval: must be positive")
+
+	switch c.Storage.Driver {
+	case DriverPostgres:
+		check(c.Postgres.Host != "", "postgres.host: must not be empty")
+		check(c.Postgres.Port != "", "postgres.port: must not be empty")
+		check(c.Postgres.User != "", "postgres.user: must not be empty")
+		check(c.Postgres.DBName != "", "postgres.dbname: must not be empty")
+	case DriverSQLite:
+		check(c.SQLite.StoragePath != "", "sqlite.storage_path: must not be empty")
+	}
+
+	check(c.HttpServer.Address != "", "http_server.address: must not be empty")
+	check(c.HttpServer.Timeout > 0, "http_server.timeout: must be positive")
+	check(c.HttpServer.IdleTimeout > 0, "http_server.idle_timeout: must be positive")
+	check(c.HttpServer.ShutdownDelay >= 0, "http_server.shutdown_delay: must not be negative")
+	check(c.HttpServer.ShutdownTimeout > 0, "http_server.shutdown_timeout: must be positive")
+	check(c.Admin.Address == "" || c.Admin.Address != c.HttpServer.Address,
+		"admin_server.address: must differ from http_server.address")
+
+	check(c.Health.ReadyTimeout > 0, "health.ready_timeout: must be positive")
+
+	check(slices.Contains([]string{ExporterNone, ExporterStdout, ExporterOTLP}, c.Tracing.Exporter),
+		"tracing.exporter: must be one of none, stdout, otlp, got %q", c.Tracing.Exporter)
+	check(c.Tracing.Exporter != ExporterOTLP || c.Tracing.Endpoint != "", "tracing.endpoint: must not be empty")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio: must be between 0 and 1")
+
+	check(c.Clicks.BufferSize > 0, "clicks.buffer_size: must be positive")
+	check(c.Clicks.BatchSize > 0, "clicks.batch_size: must be positive")
+	check(c.Clicks.FlushInterval > 0, "clicks.flush_interval: must be positive")
+
+	check(c.Auth.AdminKey != "", "auth.admin_key: is required")
+
+	check(c.RateLimit.URLRPS >= 0, "rate_limit.url_rps: must not be negative")
+	check(c.RateLimit.URLRPS == 0 || c.RateLimit.URLBurst > 0, "rate_limit.url_burst: must be positive")
+	check(c.RateLimit.RedirectRPS >= 0, "rate_limit.redirect_rps: must not be negative")
+	check(c.RateLimit.RedirectRPS == 0 || c.RateLimit.RedirectBurst > 0, "rate_limit.redirect_burst: must be positive")
+	check(c.RateLimit.AuthFailRPS >= 0, "rate_limit.auth_fail_rps: must not be negative")
+	check(c.RateLimit.AuthFailRPS == 0 || c.RateLimit.AuthFailBurst > 0, "rate_limit.auth_fail_burst: must be positive")
+
+	check(slices.Contains([]string{StrategyRandom, StrategyCounter}, c.Aliases.Strategy),
+		"aliases.strategy: must be one of random, counter, got %q", c.Aliases.Strategy)
+	check(c.Aliases.Length > 0, "aliases.length: must be positive")
+	check(slices.Contains([]string{AlphabetBase62, AlphabetLowercase, AlphabetUnambiguous}, c.Aliases.Alphabet),
+		"aliases.alphabet: must be one of base62, lowercase, unambiguous, got %q", c.Aliases.Alphabet)
+	check(c.Aliases.MaxCustomLength > 0, "aliases.max_custom_length: must be positive")
+
+	check(c.Cache.Size >= 0, "cache.size: must not be negative")
+	check(c.Cache.Size == 0 || c.Cache.TTL > 0, "cache.ttl: must be positive")
+	check(c.Cache.NegativeTTL >= 0, "cache.negative_ttl: must not be negative")
+
+	return errors.Join(errs...)
+}
+
+// fetchConfigPath returns the config path and whether it was set explicitly.
+// Priority: flag > env > default.
+func fetchConfigPath() (string, bool) {
+	var res string
+
+	flag.StringVar(&res, "config", "", "path to config file")
+	flag.Parse()
+
+	if res == "" {
+		res = os.Getenv("CONFIG_PATH")
+	}
+	if res == "" {
+		return defaultConfigPath, false
 	}
 
-	return &cfg
+	return res, true
 }
@@ -0,0 +1,88 @@
+package config_test
+
+import (
+	// project
+	"go-url-shortener/internal/config"
+
+	// embedded
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	// external
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_FromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+env: "dev"
+storage:
+  driver: "sqlite"
+sqlite:
+  storage_path: "/tmp/storage.db"
+http_server:
+  address: ":9090"
+auth:
+  admin_key: "us_admin"
+`), 0o600))
+
+	// env vars override the file
+	t.Setenv("HTTP_SERVER_ADDRESS", ":9091")
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, config.EnvDev, cfg.Env)
+	assert.Equal(t, config.DriverSQLite, cfg.Storage.Driver)
+	assert.Equal(t, "/tmp/storage.db", cfg.SQLite.StoragePath)
+	assert.Equal(t, ":9091", cfg.HttpServer.Address)
+	assert.Equal(t, 4*time.Second, cfg.HttpServer.Timeout)
+}
+
+func TestLoad_FromEnv(t *testing.T) {
+	t.Setenv("ENV", "prod")
+	t.Setenv("STORAGE_DRIVER", "memory")
+	t.Setenv("AUTH_ADMIN_KEY", "us_admin")
+	t.Setenv("CLICKS_BATCH_SIZE", "10")
+
+	cfg, err := config.Load("")
+	require.NoError(t, err)
+
+	assert.Equal(t, config.EnvProd, cfg.Env)
+	assert.Equal(t, config.DriverMemory, cfg.Storage.Driver)
+	assert.Equal(t, "us_admin", cfg.Auth.AdminKey)
+	assert.Equal(t, 10, cfg.Clicks.BatchSize)
+}
+
+func TestLoad_ReportsAllInvalidFields(t *testing.T) {
+	t.Setenv("ENV", "staging")
+	t.Setenv("STORAGE_DRIVER", "mysql")
+	t.Setenv("HTTP_SERVER_TIMEOUT", "0s")
+	t.Setenv("RATE_LIMIT_URL_RPS", "-1")
+	t.Setenv("RATE_LIMIT_AUTH_FAIL_BURST", "0")
+	t.Setenv("ALIASES_ALPHABET", "hex")
+	t.Setenv("ALIASES_STRATEGY", "uuid")
+	t.Setenv("CACHE_SIZE", "-1")
+	t.Setenv("TRACING_EXPORTER", "jaeger")
+
+	_, err := config.Load("")
+	require.Error(t, err)
+
+	for _, field := range []string{
+		"env:",
+		"storage.driver:",
+		"http_server.timeout:",
+		"auth.admin_key:",
+		"rate_limit.url_rps:",
+		"rate_limit.auth_fail_burst:",
+		"aliases.alphabet:",
+		"aliases.strategy:",
+		"cache.size:",
+		"tracing.exporter:",
+	} {
+		assert.Contains(t, err.Error(), field)
+	}
+}
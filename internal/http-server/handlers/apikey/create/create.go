@@ -0,0 +1,96 @@
+package create
+
+import (
+	// project
+	"go-url-shortener/internal/lib/api/response"
+	"go-url-shortener/internal/lib/apikey"
+	"go-url-shortener/internal/lib/logger/sl"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"context"
+	"log/slog"
+	"net/http"
+
+	// external
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+// Request creates a key with the "user" role unless Role says otherwise.
+type Request struct {
+	Name string `json:"name" validate:"required"`
+	Role string `json:"role,omitempty" validate:"omitempty,oneof=admin user"`
+}
+
+// Response carries the plaintext key, it is never shown again.
+type Response struct {
+	response.Response
+	ID   int64  `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	Role string `json:"role,omitempty"`
+	Key  string `json:"key,omitempty"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=APIKeySaver --output=mocks --outpkg=mocks --with-expecter
+type APIKeySaver interface {
+	// SaveAPIKey stores a key by its hash and returns its id.
+	SaveAPIKey(ctx context.Context, name string, keyHash string, role string) (int64, error)
+}
+
+func New(log *slog.Logger, keySaver APIKeySaver) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.apikey.create.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.TraceID(r.Context()),
+		)
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			response.Render(w, r, http.StatusBadRequest, response.Error("failed to decode request"))
+			return
+		}
+
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+			log.Error("invalid request", sl.Err(err))
+			response.Render(w, r, http.StatusBadRequest, response.ValidationError(validateErr))
+			return
+		}
+
+		role := req.Role
+		if role == "" {
+			role = storage.RoleUser
+		}
+
+		key, err := apikey.Generate()
+		if err != nil {
+			log.Error("failed to generate api key", sl.Err(err))
+			response.RenderError(w, r, err, "failed to create api key")
+			return
+		}
+
+		id, err := keySaver.SaveAPIKey(r.Context(), req.Name, apikey.Hash(key), role)
+		if err != nil {
+			log.Error("failed to save api key", sl.Err(err))
+			response.RenderError(w, r, err, "failed to create api key")
+			return
+		}
+
+		log.Info("api key created", slog.Int64("id", id), slog.String("role", role))
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			ID:       id,
+			Name:     req.Name,
+			Role:     role,
+			Key:      key,
+		})
+	}
+}
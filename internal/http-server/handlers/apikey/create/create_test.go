@@ -0,0 +1,104 @@
+package create_test
+
+import (
+	// project
+	"go-url-shortener/internal/http-server/handlers/apikey/create"
+	"go-url-shortener/internal/http-server/handlers/mocks"
+	"go-url-shortener/internal/lib/apikey"
+	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
+
+	// embedded
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// external
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		body       string
+		role       string
+		mockError  error
+		statusCode int
+		respError  string
+	}{
+		{
+			name:       "Success",
+			body:       `{"name":"ci"}`,
+			role:       "user",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Admin role",
+			body:       `{"name":"ops","role":"admin"}`,
+			role:       "admin",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty name",
+			body:       `{"name":""}`,
+			statusCode: http.StatusBadRequest,
+			respError:  "field Name is a required field",
+		},
+		{
+			name:       "Unknown role",
+			body:       `{"name":"ci","role":"root"}`,
+			statusCode: http.StatusBadRequest,
+			respError:  "field Role is not valid",
+		},
+		{
+			name:       "Invalid body",
+			body:       `{`,
+			statusCode: http.StatusBadRequest,
+			respError:  "failed to decode request",
+		},
+		{
+			name:       "Storage error",
+			body:       `{"name":"ci"}`,
+			role:       "user",
+			mockError:  errors.New("db down"),
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to create api key",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			saver := mocks.NewAPIKeySaver(t)
+
+			var savedHash string
+			if tc.role != "" {
+				saver.On("SaveAPIKey", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), tc.role).
+					Run(func(args mock.Arguments) { savedHash = args.String(2) }).
+					Return(int64(1), tc.mockError).
+					Once()
+			}
+
+			handler := create.New(slogdiscard.NewDiscardLogger(), saver)
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/keys", bytes.NewReader([]byte(tc.body)))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var resp create.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tc.respError, resp.Error)
+
+			if tc.statusCode == http.StatusOK {
+				// only the hash of the returned key is stored
+				assert.Equal(t, apikey.Hash(resp.Key), savedHash)
+				assert.Equal(t, tc.role, resp.Role)
+			}
+		})
+	}
+}
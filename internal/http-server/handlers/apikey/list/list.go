@@ -0,0 +1,77 @@
+package list
+
+import (
+	// project
+	"go-url-shortener/internal/lib/api/response"
+	"go-url-shortener/internal/lib/logger/sl"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	// external
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// Key never includes the key itself, only its metadata.
+type Key struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Role      string     `json:"role"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+type Response struct {
+	response.Response
+	Keys []Key `json:"keys"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=APIKeyLister --output=mocks --outpkg=mocks --with-expecter
+type APIKeyLister interface {
+	ListAPIKeys(ctx context.Context) ([]storage.APIKey, error)
+}
+
+// New lists every api key, revoked ones included.
+func New(log *slog.Logger, keyLister APIKeyLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.apikey.list.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.TraceID(r.Context()),
+		)
+
+		keys, err := keyLister.ListAPIKeys(r.Context())
+		if err != nil {
+			log.Error("failed to list api keys", sl.Err(err))
+			response.RenderError(w, r, err, "internal error")
+			return
+		}
+
+		resp := Response{
+			Response: response.OK(),
+			Keys:     make([]Key, 0, len(keys)),
+		}
+		for _, k := range keys {
+			key := Key{
+				ID:        k.ID,
+				Name:      k.Name,
+				Role:      k.Role,
+				CreatedAt: k.CreatedAt,
+			}
+			if !k.RevokedAt.IsZero() {
+				revokedAt := k.RevokedAt
+				key.RevokedAt = &revokedAt
+			}
+			resp.Keys = append(resp.Keys, key)
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
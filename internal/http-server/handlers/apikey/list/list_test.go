@@ -0,0 +1,81 @@
+package list_test
+
+import (
+	// project
+	"go-url-shortener/internal/http-server/handlers/apikey/list"
+	"go-url-shortener/internal/http-server/handlers/mocks"
+	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	// external
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name       string
+		keys       []storage.APIKey
+		mockError  error
+		statusCode int
+		respError  string
+	}{
+		{
+			name: "Success",
+			keys: []storage.APIKey{
+				{ID: 1, Name: "ci", Role: storage.RoleUser, CreatedAt: createdAt},
+				{ID: 2, Name: "old", Role: storage.RoleUser, CreatedAt: createdAt, RevokedAt: createdAt.Add(time.Hour)},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Storage error",
+			mockError:  errors.New("db down"),
+			statusCode: http.StatusInternalServerError,
+			respError:  "internal error",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lister := mocks.NewAPIKeyLister(t)
+			lister.On("ListAPIKeys", mock.Anything).
+				Return(tc.keys, tc.mockError).
+				Once()
+
+			handler := list.New(slogdiscard.NewDiscardLogger(), lister)
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/keys", nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var resp list.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tc.respError, resp.Error)
+
+			require.Len(t, resp.Keys, len(tc.keys))
+			for i, key := range tc.keys {
+				assert.Equal(t, key.ID, resp.Keys[i].ID)
+				assert.Equal(t, key.Name, resp.Keys[i].Name)
+				assert.Equal(t, !key.RevokedAt.IsZero(), resp.Keys[i].RevokedAt != nil)
+			}
+		})
+	}
+}
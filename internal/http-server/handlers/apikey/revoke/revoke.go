@@ -0,0 +1,60 @@
+package revoke
+
+import (
+	// project
+	"go-url-shortener/internal/lib/api/response"
+	"go-url-shortener/internal/lib/logger/sl"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	// external
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=APIKeyRevoker --output=mocks --outpkg=mocks --with-expecter
+type APIKeyRevoker interface {
+	// RevokeAPIKey deactivates an active key.
+	RevokeAPIKey(ctx context.Context, id int64) error
+}
+
+func New(log *slog.Logger, keyRevoker APIKeyRevoker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.apikey.revoke.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.TraceID(r.Context()),
+		)
+
+		id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || id < 1 {
+			log.Info("invalid api key id", slog.String("id", chi.URLParam(r, "id")))
+			response.Render(w, r, http.StatusBadRequest, response.Error("invalid id"))
+			return
+		}
+
+		err = keyRevoker.RevokeAPIKey(r.Context(), id)
+		switch {
+		case errors.Is(err, storage.ErrAPIKeyNotFound):
+			log.Info("api key not found", slog.Int64("id", id))
+		case err != nil:
+			log.Error("failed to revoke api key", sl.Err(err))
+		}
+		if err != nil {
+			response.RenderError(w, r, err, "failed to revoke api key")
+			return
+		}
+
+		log.Info("api key revoked", slog.Int64("id", id))
+		render.JSON(w, r, response.OK())
+	}
+}
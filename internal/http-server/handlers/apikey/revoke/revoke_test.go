@@ -0,0 +1,86 @@
+package revoke_test
+
+import (
+	// project
+	"go-url-shortener/internal/http-server/handlers/apikey/revoke"
+	"go-url-shortener/internal/http-server/handlers/mocks"
+	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// external
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokeHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		id         string
+		mockID     int64
+		mockError  error
+		statusCode int
+		respError  string
+	}{
+		{
+			name:       "Success",
+			id:         "3",
+			mockID:     3,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Not found",
+			id:         "4",
+			mockID:     4,
+			mockError:  storage.ErrAPIKeyNotFound,
+			statusCode: http.StatusNotFound,
+			respError:  "not found",
+		},
+		{
+			name:       "Invalid id",
+			id:         "abc",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid id",
+		},
+		{
+			name:       "Storage error",
+			id:         "5",
+			mockID:     5,
+			mockError:  errors.New("db down"),
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to revoke api key",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			revoker := mocks.NewAPIKeyRevoker(t)
+			if tc.mockID != 0 {
+				revoker.On("RevokeAPIKey", mock.Anything, tc.mockID).
+					Return(tc.mockError).
+					Once()
+			}
+
+			r := chi.NewRouter()
+			r.Delete("/admin/keys/{id}", revoke.New(slogdiscard.NewDiscardLogger(), revoker))
+
+			req := httptest.NewRequest(http.MethodDelete, "/admin/keys/"+tc.id, nil)
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var resp map[string]string
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tc.respError, resp["error"])
+		})
+	}
+}
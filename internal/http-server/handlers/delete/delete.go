@@ -2,11 +2,13 @@ package delete
 
 import (
 	// project
+	"go-url-shortener/internal/http-server/middleware/auth"
 	"go-url-shortener/internal/lib/api/response"
 	"go-url-shortener/internal/lib/logger/sl"
 	"go-url-shortener/internal/storage"
 	
 	// embedded
+	"context"
 	"errors"
 	"log/slog"
 	"net/http"
@@ -19,34 +21,41 @@ import (
 
 //go:generate go run github.com/vektra/mockery/v2@latest --name=URLDeleter --output=mocks --outpkg=mocks --with-expecter
 type URLDeleter interface {
-	DeleteURL(alias string) error
+	// DeleteURL removes the url unless caller is neither its owner nor an admin.
+	DeleteURL(ctx context.Context, alias string, caller storage.APIKey) error
 }
 
 func New(log *slog.Logger, urlDeleter URLDeleter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.url.redirect.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.TraceID(r.Context()),
 		)
 
 		alias := chi.URLParam(r, "alias")
 		if alias == "" {
 			log.Info("alias is empty")
-			render.JSON(w, r, response.Error("invalid alias"))
+			response.Render(w, r, http.StatusBadRequest, response.Error("invalid alias"))
 			return
 		}
 
-		err := urlDeleter.DeleteURL(alias)
-		if errors.Is(err, storage.ErrURLNotFound) {
+		// without an identity the caller owns nothing and is refused
+		caller, _ := auth.FromContext(r.Context())
+
+		err := urlDeleter.DeleteURL(r.Context(), alias, caller)
+		switch {
+		case errors.Is(err, storage.ErrURLNotFound):
 			log.Info("url not found", "alias", alias)
-			render.JSON(w, r, response.Error("not found"))
-			return
+		case errors.Is(err, storage.ErrForbidden):
+			log.Info("url belongs to another owner", "alias", alias, slog.Int64("key_id", caller.ID))
+		case err != nil:
+			log.Info("failed to delete url", sl.Err(err))
 		}
 		if err != nil {
-			log.Info("failed to delete url", sl.Err(err))
-			render.JSON(w, r, response.Error("internal error"))
+			response.RenderError(w, r, err, "failed to delete url")
 			return
 		}
 		log.Info("url deleted successfully")
@@ -4,6 +4,7 @@ import (
 	// project
 	"go-url-shortener/internal/http-server/handlers/delete"
 	"go-url-shortener/internal/http-server/handlers/mocks"
+	"go-url-shortener/internal/http-server/middleware/auth"
 	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
 	"go-url-shortener/internal/storage"
 	
@@ -17,6 +18,7 @@ import (
 	// external
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 )
 
@@ -37,24 +39,33 @@ func TestDeleteHandler(t *testing.T) {
 			name:       "URL Not Found",
 			alias:      "missing",
 			mockError:  storage.ErrURLNotFound,
-			statusCode: http.StatusOK,
+			statusCode: http.StatusNotFound,
 			respError:  "not found",
 		},
+		{
+			name:       "Foreign URL",
+			alias:      "foreign",
+			mockError:  storage.ErrForbidden,
+			statusCode: http.StatusForbidden,
+			respError:  "forbidden",
+		},
 		{
 			name:       "Internal Error",
 			alias:      "broken",
 			mockError:  errors.New("db down"),
-			statusCode: http.StatusOK,
-			respError:  "internal error",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to delete url",
 		},
 	}
 
+	caller := storage.APIKey{ID: 7, Role: storage.RoleUser}
+
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			deleter := mocks.NewURLDeleter(t)
 
 			deleter.
-				On("DeleteURL", tc.alias).
+				On("DeleteURL", mock.Anything, tc.alias, caller).
 				Return(tc.mockError).
 				Once()
 
@@ -69,6 +80,7 @@ func TestDeleteHandler(t *testing.T) {
 				"/"+tc.alias,
 				nil,
 			)
+			req = req.WithContext(auth.WithKey(req.Context(), caller))
 			rec := httptest.NewRecorder()
 
 			r.ServeHTTP(rec, req)
@@ -0,0 +1,20 @@
+package live
+
+import (
+	// project
+	"go-url-shortener/internal/lib/api/response"
+
+	// embedded
+	"net/http"
+
+	// external
+	"github.com/go-chi/render"
+)
+
+// New reports that the process is up and serving http. It checks nothing
+// else, a storage outage must not get the service restarted.
+func New() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.JSON(w, r, response.OK())
+	}
+}
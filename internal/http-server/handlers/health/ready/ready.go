@@ -0,0 +1,55 @@
+package ready
+
+import (
+	// project
+	"go-url-shortener/internal/lib/api/response"
+	"go-url-shortener/internal/lib/logger/sl"
+
+	// embedded
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	// external
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=Pinger --output=mocks --outpkg=mocks --with-expecter
+type Pinger interface {
+	// Ping checks that the storage backend is reachable.
+	Ping(ctx context.Context) error
+}
+
+// New reports whether the service may receive traffic: the storage answers
+// a ping within timeout and shutdown is not closed yet.
+func New(log *slog.Logger, pinger Pinger, timeout time.Duration, shutdown <-chan struct{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.health.ready.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.TraceID(r.Context()),
+		)
+
+		select {
+		case <-shutdown:
+			response.Render(w, r, http.StatusServiceUnavailable, response.Error("shutting down"))
+			return
+		default:
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		if err := pinger.Ping(ctx); err != nil {
+			log.Error("storage is not ready", sl.Err(err))
+			response.Render(w, r, http.StatusServiceUnavailable, response.Error("storage unavailable"))
+			return
+		}
+
+		render.JSON(w, r, response.OK())
+	}
+}
@@ -0,0 +1,79 @@
+package ready_test
+
+import (
+	// project
+	"go-url-shortener/internal/http-server/handlers/health/ready"
+	"go-url-shortener/internal/http-server/handlers/mocks"
+	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
+
+	// embedded
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	// external
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadyHandler(t *testing.T) {
+	cases := []struct {
+		name         string
+		shuttingDown bool
+		ping         bool
+		mockError    error
+		statusCode   int
+		respError    string
+	}{
+		{
+			name:       "Ready",
+			ping:       true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Storage unavailable",
+			ping:       true,
+			mockError:  errors.New("connection refused"),
+			statusCode: http.StatusServiceUnavailable,
+			respError:  "storage unavailable",
+		},
+		{
+			name:         "Shutting down",
+			shuttingDown: true,
+			statusCode:   http.StatusServiceUnavailable,
+			respError:    "shutting down",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pinger := mocks.NewPinger(t)
+			if tc.ping {
+				pinger.On("Ping", mock.Anything).
+					Return(tc.mockError).
+					Once()
+			}
+
+			shutdown := make(chan struct{})
+			if tc.shuttingDown {
+				close(shutdown)
+			}
+
+			handler := ready.New(slogdiscard.NewDiscardLogger(), pinger, time.Second, shutdown)
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var resp map[string]string
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			assert.Equal(t, tc.respError, resp["error"])
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package info
+
+import (
+	// project
+	"go-url-shortener/internal/lib/api/response"
+	"go-url-shortener/internal/lib/logger/sl"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	// external
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+type Response struct {
+	response.Response
+	Alias       string     `json:"alias,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	OriginalURL string     `json:"original_url,omitempty"` // set when the url was normalized
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Expired     bool       `json:"expired"`
+	Clicks      int64      `json:"clicks"`
+	OwnerID     int64      `json:"owner_id,omitempty"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=URLInfoGetter --output=mocks --outpkg=mocks --with-expecter
+type URLInfoGetter interface {
+	GetURLInfo(ctx context.Context, alias string) (storage.URLInfo, error)
+}
+
+// New resolves an alias without redirecting.
+func New(log *slog.Logger, urlInfoGetter URLInfoGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.info.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.TraceID(r.Context()),
+		)
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.Info("alias is empty")
+			response.Render(w, r, http.StatusBadRequest, response.Error("invalid alias"))
+			return
+		}
+
+		info, err := urlInfoGetter.GetURLInfo(r.Context(), alias)
+		switch {
+		case errors.Is(err, storage.ErrURLNotFound):
+			log.Info("url not found", "alias", alias)
+		case err != nil:
+			log.Error("failed to get url info", sl.Err(err))
+		}
+		if err != nil {
+			response.RenderError(w, r, err, "failed to get url info")
+			return
+		}
+
+		resp := Response{
+			Response:  response.OK(),
+			Alias:     info.Alias,
+			URL:       info.URL.URL,
+			CreatedAt: info.CreatedAt,
+			Clicks:    info.Clicks,
+			OwnerID:   info.OwnerID,
+		}
+		if info.OriginalURL != info.URL.URL {
+			resp.OriginalURL = info.OriginalURL
+		}
+		if !info.ExpiresAt.IsZero() {
+			resp.ExpiresAt = &info.ExpiresAt
+			resp.Expired = !info.ExpiresAt.After(time.Now())
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
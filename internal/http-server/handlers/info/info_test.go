@@ -0,0 +1,104 @@
+package info_test
+
+import (
+	// project
+	"go-url-shortener/internal/http-server/handlers/info"
+	"go-url-shortener/internal/http-server/handlers/mocks"
+	"go-url-shortener/internal/lib/api"
+	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	// external
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfoHandler(t *testing.T) {
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+
+	cases := []struct {
+		name      string
+		alias     string
+		info      storage.URLInfo
+		mockError error
+		respError string
+	}{
+		{
+			name:  "Success",
+			alias: "test_alias",
+			info: storage.URLInfo{
+				URL:    storage.URL{Alias: "test_alias", URL: "https://google.com/", OriginalURL: "HTTPS://Google.com", CreatedAt: createdAt, OwnerID: 3},
+				Clicks: 42,
+			},
+		},
+		{
+			name:  "Expired",
+			alias: "expired_alias",
+			info: storage.URLInfo{
+				URL: storage.URL{Alias: "expired_alias", URL: "https://google.com", OriginalURL: "https://google.com", CreatedAt: createdAt, ExpiresAt: expiresAt},
+			},
+		},
+		{
+			name:      "URL Not Found",
+			alias:     "missing",
+			mockError: storage.ErrURLNotFound,
+			respError: "not found",
+		},
+		{
+			name:      "Internal Error",
+			alias:     "broken",
+			mockError: errors.New("db down"),
+			respError: "failed to get url info",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			getter := mocks.NewURLInfoGetter(t)
+			getter.On("GetURLInfo", mock.Anything, tc.alias).
+				Return(tc.info, tc.mockError).
+				Once()
+
+			r := chi.NewRouter()
+			r.Get("/url/{alias}", info.New(slogdiscard.NewDiscardLogger(), getter))
+
+			ts := httptest.NewServer(r)
+			defer ts.Close()
+
+			got, err := api.GetInfo(ts.URL+"/url/"+tc.alias, "")
+			if tc.respError != "" {
+				require.ErrorIs(t, err, api.ErrInvalidStatusCode)
+				assert.Contains(t, err.Error(), tc.respError)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.info.URL.URL, got.URL)
+			if tc.info.OriginalURL != tc.info.URL.URL {
+				assert.Equal(t, tc.info.OriginalURL, got.OriginalURL)
+			} else {
+				assert.Empty(t, got.OriginalURL)
+			}
+			assert.Equal(t, tc.info.Clicks, got.Clicks)
+			assert.Equal(t, tc.info.OwnerID, got.OwnerID)
+			assert.True(t, createdAt.Equal(got.CreatedAt))
+			if tc.info.ExpiresAt.IsZero() {
+				assert.Nil(t, got.ExpiresAt)
+				assert.False(t, got.Expired)
+			} else {
+				require.NotNil(t, got.ExpiresAt)
+				assert.True(t, expiresAt.Equal(*got.ExpiresAt))
+				assert.True(t, got.Expired)
+			}
+		})
+	}
+}
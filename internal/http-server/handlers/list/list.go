@@ -0,0 +1,136 @@
+package list
+
+import (
+	// project
+	"go-url-shortener/internal/lib/api/response"
+	"go-url-shortener/internal/lib/logger/sl"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"context"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	// external
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 100
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+type URL struct {
+	Alias       string    `json:"alias"`
+	URL         string    `json:"url"`
+	OriginalURL string    `json:"original_url,omitempty"` // set when the url was normalized
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Response carries NextCursor only when there are more urls to fetch.
+type Response struct {
+	response.Response
+	URLs       []URL  `json:"urls"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=URLLister --output=mocks --outpkg=mocks --with-expecter
+type URLLister interface {
+	ListURLs(ctx context.Context, filter storage.ListFilter) ([]storage.URL, error)
+}
+
+// New lists saved urls. Query params: alias_prefix, url_contains, limit
+// and cursor, the latter taken from next_cursor of the previous page.
+func New(log *slog.Logger, urlLister URLLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.list.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.TraceID(r.Context()),
+		)
+
+		query := r.URL.Query()
+
+		limit := defaultLimit
+		if raw := query.Get("limit"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 || n > maxLimit {
+				log.Info("invalid limit", slog.String("limit", raw))
+				response.Render(w, r, http.StatusBadRequest, response.Error("limit must be between 1 and 100"))
+				return
+			}
+			limit = n
+		}
+
+		after, err := decodeCursor(query.Get("cursor"))
+		if err != nil {
+			log.Info("invalid cursor", slog.String("cursor", query.Get("cursor")))
+			response.Render(w, r, http.StatusBadRequest, response.Error("invalid cursor"))
+			return
+		}
+
+		// one extra row tells whether there is a next page
+		urls, err := urlLister.ListURLs(r.Context(), storage.ListFilter{
+			AliasPrefix: query.Get("alias_prefix"),
+			URLContains: query.Get("url_contains"),
+			After:       after,
+			Limit:       limit + 1,
+		})
+		if err != nil {
+			log.Error("failed to list urls", sl.Err(err))
+			response.RenderError(w, r, err, "internal error")
+			return
+		}
+
+		resp := Response{
+			Response: response.OK(),
+			URLs:     make([]URL, 0, min(len(urls), limit)),
+		}
+		if len(urls) > limit {
+			urls = urls[:limit]
+			resp.NextCursor = encodeCursor(urls[len(urls)-1].ID)
+		}
+		for _, u := range urls {
+			item := URL{
+				Alias:     u.Alias,
+				URL:       u.URL,
+				CreatedAt: u.CreatedAt,
+			}
+			if u.OriginalURL != u.URL {
+				item.OriginalURL = u.OriginalURL
+			}
+			resp.URLs = append(resp.URLs, item)
+		}
+
+		render.JSON(w, r, resp)
+	}
+}
+
+// cursors are opaque to clients, they just carry the last seen id
+func encodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeCursor(cursor string) (int64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errInvalidCursor
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id < 0 {
+		return 0, errInvalidCursor
+	}
+	return id, nil
+}
@@ -0,0 +1,80 @@
+package list_test
+
+import (
+	// project
+	"go-url-shortener/internal/http-server/handlers/list"
+	"go-url-shortener/internal/http-server/handlers/mocks"
+	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	// external
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func urls(from, to int64) []storage.URL {
+	var res []storage.URL
+	for id := from; id <= to; id++ {
+		res = append(res, storage.URL{
+			ID:        id,
+			Alias:     fmt.Sprintf("alias_%d", id),
+			URL:       "https://google.com",
+			CreatedAt: time.Now(),
+		})
+	}
+	return res
+}
+
+func TestListHandler(t *testing.T) {
+	lister := mocks.NewURLLister(t)
+	handler := list.New(slogdiscard.NewDiscardLogger(), lister)
+
+	get := func(query string) list.Response {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/"+query, nil))
+
+		var resp list.Response
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		return resp
+	}
+
+	// first page: there are more rows than the limit
+	lister.On("ListURLs", mock.Anything, storage.ListFilter{AliasPrefix: "alias_", URLContains: "google", Limit: 3}).
+		Return(urls(1, 3), nil).
+		Once()
+
+	resp := get("?limit=2&alias_prefix=alias_&url_contains=google")
+	require.Empty(t, resp.Error)
+	require.Len(t, resp.URLs, 2)
+	assert.Equal(t, "alias_2", resp.URLs[1].Alias)
+	require.NotEmpty(t, resp.NextCursor)
+
+	// second page continues after the last returned id
+	lister.On("ListURLs", mock.Anything, storage.ListFilter{After: 2, Limit: 3}).
+		Return(urls(3, 3), nil).
+		Once()
+
+	resp = get("?limit=2&cursor=" + resp.NextCursor)
+	require.Empty(t, resp.Error)
+	require.Len(t, resp.URLs, 1)
+	assert.Empty(t, resp.NextCursor)
+
+	// errors
+	assert.Equal(t, "invalid cursor", get("?cursor=***").Error)
+	assert.Equal(t, "limit must be between 1 and 100", get("?limit=1000").Error)
+
+	lister.On("ListURLs", mock.Anything, storage.ListFilter{Limit: 51}).
+		Return(nil, errors.New("db down")).
+		Once()
+	assert.Equal(t, "internal error", get("").Error)
+}
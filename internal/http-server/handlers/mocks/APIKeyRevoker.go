@@ -0,0 +1,83 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// APIKeyRevoker is an autogenerated mock type for the APIKeyRevoker type
+type APIKeyRevoker struct {
+	mock.Mock
+}
+
+type APIKeyRevoker_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *APIKeyRevoker) EXPECT() *APIKeyRevoker_Expecter {
+	return &APIKeyRevoker_Expecter{mock: &_m.Mock}
+}
+
+// RevokeAPIKey provides a mock function with given fields: ctx, id
+func (_m *APIKeyRevoker) RevokeAPIKey(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAPIKey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// APIKeyRevoker_RevokeAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeAPIKey'
+type APIKeyRevoker_RevokeAPIKey_Call struct {
+	*mock.Call
+}
+
+// RevokeAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+func (_e *APIKeyRevoker_Expecter) RevokeAPIKey(ctx interface{}, id interface{}) *APIKeyRevoker_RevokeAPIKey_Call {
+	return &APIKeyRevoker_RevokeAPIKey_Call{Call: _e.mock.On("RevokeAPIKey", ctx, id)}
+}
+
+func (_c *APIKeyRevoker_RevokeAPIKey_Call) Run(run func(ctx context.Context, id int64)) *APIKeyRevoker_RevokeAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64))
+	})
+	return _c
+}
+
+func (_c *APIKeyRevoker_RevokeAPIKey_Call) Return(_a0 error) *APIKeyRevoker_RevokeAPIKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *APIKeyRevoker_RevokeAPIKey_Call) RunAndReturn(run func(context.Context, int64) error) *APIKeyRevoker_RevokeAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAPIKeyRevoker creates a new instance of APIKeyRevoker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAPIKeyRevoker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *APIKeyRevoker {
+	mock := &APIKeyRevoker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,69 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	storage "go-url-shortener/internal/storage"
+)
+
+// ClickRecorder is an autogenerated mock type for the ClickRecorder type
+type ClickRecorder struct {
+	mock.Mock
+}
+
+type ClickRecorder_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ClickRecorder) EXPECT() *ClickRecorder_Expecter {
+	return &ClickRecorder_Expecter{mock: &_m.Mock}
+}
+
+// Record provides a mock function with given fields: click
+func (_m *ClickRecorder) Record(click storage.Click) {
+	_m.Called(click)
+}
+
+// ClickRecorder_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type ClickRecorder_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - click storage.Click
+func (_e *ClickRecorder_Expecter) Record(click interface{}) *ClickRecorder_Record_Call {
+	return &ClickRecorder_Record_Call{Call: _e.mock.On("Record", click)}
+}
+
+func (_c *ClickRecorder_Record_Call) Run(run func(click storage.Click)) *ClickRecorder_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(storage.Click))
+	})
+	return _c
+}
+
+func (_c *ClickRecorder_Record_Call) Return() *ClickRecorder_Record_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ClickRecorder_Record_Call) RunAndReturn(run func(storage.Click)) *ClickRecorder_Record_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewClickRecorder creates a new instance of ClickRecorder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewClickRecorder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ClickRecorder {
+	mock := &ClickRecorder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,82 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Pinger is an autogenerated mock type for the Pinger type
+type Pinger struct {
+	mock.Mock
+}
+
+type Pinger_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Pinger) EXPECT() *Pinger_Expecter {
+	return &Pinger_Expecter{mock: &_m.Mock}
+}
+
+// Ping provides a mock function with given fields: ctx
+func (_m *Pinger) Ping(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Ping")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Pinger_Ping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Ping'
+type Pinger_Ping_Call struct {
+	*mock.Call
+}
+
+// Ping is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Pinger_Expecter) Ping(ctx interface{}) *Pinger_Ping_Call {
+	return &Pinger_Ping_Call{Call: _e.mock.On("Ping", ctx)}
+}
+
+func (_c *Pinger_Ping_Call) Run(run func(ctx context.Context)) *Pinger_Ping_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Pinger_Ping_Call) Return(_a0 error) *Pinger_Ping_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Pinger_Ping_Call) RunAndReturn(run func(context.Context) error) *Pinger_Ping_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPinger creates a new instance of Pinger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPinger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Pinger {
+	mock := &Pinger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
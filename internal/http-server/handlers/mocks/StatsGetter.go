@@ -0,0 +1,96 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "go-url-shortener/internal/storage"
+)
+
+// StatsGetter is an autogenerated mock type for the StatsGetter type
+type StatsGetter struct {
+	mock.Mock
+}
+
+type StatsGetter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *StatsGetter) EXPECT() *StatsGetter_Expecter {
+	return &StatsGetter_Expecter{mock: &_m.Mock}
+}
+
+// GetStats provides a mock function with given fields: ctx, alias, days
+func (_m *StatsGetter) GetStats(ctx context.Context, alias string, days int) (storage.Stats, error) {
+	ret := _m.Called(ctx, alias, days)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStats")
+	}
+
+	var r0 storage.Stats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) (storage.Stats, error)); ok {
+		return rf(ctx, alias, days)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) storage.Stats); ok {
+		r0 = rf(ctx, alias, days)
+	} else {
+		r0 = ret.Get(0).(storage.Stats)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, alias, days)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StatsGetter_GetStats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStats'
+type StatsGetter_GetStats_Call struct {
+	*mock.Call
+}
+
+// GetStats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - alias string
+//   - days int
+func (_e *StatsGetter_Expecter) GetStats(ctx interface{}, alias interface{}, days interface{}) *StatsGetter_GetStats_Call {
+	return &StatsGetter_GetStats_Call{Call: _e.mock.On("GetStats", ctx, alias, days)}
+}
+
+func (_c *StatsGetter_GetStats_Call) Run(run func(ctx context.Context, alias string, days int)) *StatsGetter_GetStats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *StatsGetter_GetStats_Call) Return(_a0 storage.Stats, _a1 error) *StatsGetter_GetStats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *StatsGetter_GetStats_Call) RunAndReturn(run func(context.Context, string, int) (storage.Stats, error)) *StatsGetter_GetStats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStatsGetter creates a new instance of StatsGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStatsGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StatsGetter {
+	mock := &StatsGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -2,7 +2,13 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "go-url-shortener/internal/storage"
+)
 
 // URLDeleter is an autogenerated mock type for the URLDeleter type
 type URLDeleter struct {
@@ -17,17 +23,17 @@ func (_m *URLDeleter) EXPECT() *URLDeleter_Expecter {
 	return &URLDeleter_Expecter{mock: &_m.Mock}
 }
 
-// DeleteURL provides a mock function with given fields: alias
-func (_m *URLDeleter) DeleteURL(alias string) error {
-	ret := _m.Called(alias)
+// DeleteURL provides a mock function with given fields: ctx, alias, caller
+func (_m *URLDeleter) DeleteURL(ctx context.Context, alias string, caller storage.APIKey) error {
+	ret := _m.Called(ctx, alias, caller)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteURL")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(alias)
+	if rf, ok := ret.Get(0).(func(context.Context, string, storage.APIKey) error); ok {
+		r0 = rf(ctx, alias, caller)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -41,14 +47,16 @@ type URLDeleter_DeleteURL_Call struct {
 }
 
 // DeleteURL is a helper method to define mock.On call
+//   - ctx context.Context
 //   - alias string
-func (_e *URLDeleter_Expecter) DeleteURL(alias interface{}) *URLDeleter_DeleteURL_Call {
-	return &URLDeleter_DeleteURL_Call{Call: _e.mock.On("DeleteURL", alias)}
+//   - caller storage.APIKey
+func (_e *URLDeleter_Expecter) DeleteURL(ctx interface{}, alias interface{}, caller interface{}) *URLDeleter_DeleteURL_Call {
+	return &URLDeleter_DeleteURL_Call{Call: _e.mock.On("DeleteURL", ctx, alias, caller)}
 }
 
-func (_c *URLDeleter_DeleteURL_Call) Run(run func(alias string)) *URLDeleter_DeleteURL_Call {
+func (_c *URLDeleter_DeleteURL_Call) Run(run func(ctx context.Context, alias string, caller storage.APIKey)) *URLDeleter_DeleteURL_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(storage.APIKey))
 	})
 	return _c
 }
@@ -58,7 +66,7 @@ func (_c *URLDeleter_DeleteURL_Call) Return(_a0 error) *URLDeleter_DeleteURL_Cal
 	return _c
 }
 
-func (_c *URLDeleter_DeleteURL_Call) RunAndReturn(run func(string) error) *URLDeleter_DeleteURL_Call {
+func (_c *URLDeleter_DeleteURL_Call) RunAndReturn(run func(context.Context, string, storage.APIKey) error) *URLDeleter_DeleteURL_Call {
 	_c.Call.Return(run)
 	return _c
 }
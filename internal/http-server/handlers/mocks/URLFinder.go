@@ -0,0 +1,94 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// URLFinder is an autogenerated mock type for the URLFinder type
+type URLFinder struct {
+	mock.Mock
+}
+
+type URLFinder_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLFinder) EXPECT() *URLFinder_Expecter {
+	return &URLFinder_Expecter{mock: &_m.Mock}
+}
+
+// FindURL provides a mock function with given fields: ctx, urlToFind, keyID
+func (_m *URLFinder) FindURL(ctx context.Context, urlToFind string, keyID int64) (string, error) {
+	ret := _m.Called(ctx, urlToFind, keyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindURL")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (string, error)); ok {
+		return rf(ctx, urlToFind, keyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) string); ok {
+		r0 = rf(ctx, urlToFind, keyID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, urlToFind, keyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLFinder_FindURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindURL'
+type URLFinder_FindURL_Call struct {
+	*mock.Call
+}
+
+// FindURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - urlToFind string
+//   - keyID int64
+func (_e *URLFinder_Expecter) FindURL(ctx interface{}, urlToFind interface{}, keyID interface{}) *URLFinder_FindURL_Call {
+	return &URLFinder_FindURL_Call{Call: _e.mock.On("FindURL", ctx, urlToFind, keyID)}
+}
+
+func (_c *URLFinder_FindURL_Call) Run(run func(ctx context.Context, urlToFind string, keyID int64)) *URLFinder_FindURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *URLFinder_FindURL_Call) Return(_a0 string, _a1 error) *URLFinder_FindURL_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLFinder_FindURL_Call) RunAndReturn(run func(context.Context, string, int64) (string, error)) *URLFinder_FindURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewURLFinder creates a new instance of URLFinder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewURLFinder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLFinder {
+	mock := &URLFinder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
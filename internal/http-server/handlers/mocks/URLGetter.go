@@ -1,31 +1,47 @@
-// Code generated by mockery v2.28.2. DO NOT EDIT.
+// Code generated by mockery v2.53.5. DO NOT EDIT.
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // URLGetter is an autogenerated mock type for the URLGetter type
 type URLGetter struct {
 	mock.Mock
 }
 
-// GetURL provides a mock function with given fields: alias
-func (_m *URLGetter) GetURL(alias string) (string, error) {
-	ret := _m.Called(alias)
+type URLGetter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLGetter) EXPECT() *URLGetter_Expecter {
+	return &URLGetter_Expecter{mock: &_m.Mock}
+}
+
+// GetURL provides a mock function with given fields: ctx, alias
+func (_m *URLGetter) GetURL(ctx context.Context, alias string) (string, error) {
+	ret := _m.Called(ctx, alias)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetURL")
+	}
 
 	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
-		return rf(alias)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, alias)
 	}
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(alias)
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, alias)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(alias)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, alias)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -33,17 +49,45 @@ func (_m *URLGetter) GetURL(alias string) (string, error) {
 	return r0, r1
 }
 
-type mockConstructorTestingTNewURLGetter interface {
-	mock.TestingT
-	Cleanup(func())
+// URLGetter_GetURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetURL'
+type URLGetter_GetURL_Call struct {
+	*mock.Call
+}
+
+// GetURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - alias string
+func (_e *URLGetter_Expecter) GetURL(ctx interface{}, alias interface{}) *URLGetter_GetURL_Call {
+	return &URLGetter_GetURL_Call{Call: _e.mock.On("GetURL", ctx, alias)}
+}
+
+func (_c *URLGetter_GetURL_Call) Run(run func(ctx context.Context, alias string)) *URLGetter_GetURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *URLGetter_GetURL_Call) Return(_a0 string, _a1 error) *URLGetter_GetURL_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLGetter_GetURL_Call) RunAndReturn(run func(context.Context, string) (string, error)) *URLGetter_GetURL_Call {
+	_c.Call.Return(run)
+	return _c
 }
 
 // NewURLGetter creates a new instance of URLGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-func NewURLGetter(t mockConstructorTestingTNewURLGetter) *URLGetter {
+// The first argument is typically a *testing.T value.
+func NewURLGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLGetter {
 	mock := &URLGetter{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
 
 	return mock
-}
\ No newline at end of file
+}
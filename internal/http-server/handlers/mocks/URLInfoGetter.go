@@ -0,0 +1,95 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "go-url-shortener/internal/storage"
+)
+
+// URLInfoGetter is an autogenerated mock type for the URLInfoGetter type
+type URLInfoGetter struct {
+	mock.Mock
+}
+
+type URLInfoGetter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLInfoGetter) EXPECT() *URLInfoGetter_Expecter {
+	return &URLInfoGetter_Expecter{mock: &_m.Mock}
+}
+
+// GetURLInfo provides a mock function with given fields: ctx, alias
+func (_m *URLInfoGetter) GetURLInfo(ctx context.Context, alias string) (storage.URLInfo, error) {
+	ret := _m.Called(ctx, alias)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetURLInfo")
+	}
+
+	var r0 storage.URLInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (storage.URLInfo, error)); ok {
+		return rf(ctx, alias)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) storage.URLInfo); ok {
+		r0 = rf(ctx, alias)
+	} else {
+		r0 = ret.Get(0).(storage.URLInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, alias)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLInfoGetter_GetURLInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetURLInfo'
+type URLInfoGetter_GetURLInfo_Call struct {
+	*mock.Call
+}
+
+// GetURLInfo is a helper method to define mock.On call
+//   - ctx context.Context
+//   - alias string
+func (_e *URLInfoGetter_Expecter) GetURLInfo(ctx interface{}, alias interface{}) *URLInfoGetter_GetURLInfo_Call {
+	return &URLInfoGetter_GetURLInfo_Call{Call: _e.mock.On("GetURLInfo", ctx, alias)}
+}
+
+func (_c *URLInfoGetter_GetURLInfo_Call) Run(run func(ctx context.Context, alias string)) *URLInfoGetter_GetURLInfo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *URLInfoGetter_GetURLInfo_Call) Return(_a0 storage.URLInfo, _a1 error) *URLInfoGetter_GetURLInfo_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLInfoGetter_GetURLInfo_Call) RunAndReturn(run func(context.Context, string) (storage.URLInfo, error)) *URLInfoGetter_GetURLInfo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewURLInfoGetter creates a new instance of URLInfoGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewURLInfoGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLInfoGetter {
+	mock := &URLInfoGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,97 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	storage "go-url-shortener/internal/storage"
+)
+
+// URLLister is an autogenerated mock type for the URLLister type
+type URLLister struct {
+	mock.Mock
+}
+
+type URLLister_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLLister) EXPECT() *URLLister_Expecter {
+	return &URLLister_Expecter{mock: &_m.Mock}
+}
+
+// ListURLs provides a mock function with given fields: ctx, filter
+func (_m *URLLister) ListURLs(ctx context.Context, filter storage.ListFilter) ([]storage.URL, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListURLs")
+	}
+
+	var r0 []storage.URL
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, storage.ListFilter) ([]storage.URL, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, storage.ListFilter) []storage.URL); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]storage.URL)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, storage.ListFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLLister_ListURLs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListURLs'
+type URLLister_ListURLs_Call struct {
+	*mock.Call
+}
+
+// ListURLs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter storage.ListFilter
+func (_e *URLLister_Expecter) ListURLs(ctx interface{}, filter interface{}) *URLLister_ListURLs_Call {
+	return &URLLister_ListURLs_Call{Call: _e.mock.On("ListURLs", ctx, filter)}
+}
+
+func (_c *URLLister_ListURLs_Call) Run(run func(ctx context.Context, filter storage.ListFilter)) *URLLister_ListURLs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(storage.ListFilter))
+	})
+	return _c
+}
+
+func (_c *URLLister_ListURLs_Call) Return(_a0 []storage.URL, _a1 error) *URLLister_ListURLs_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *URLLister_ListURLs_Call) RunAndReturn(run func(context.Context, storage.ListFilter) ([]storage.URL, error)) *URLLister_ListURLs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewURLLister creates a new instance of URLLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewURLLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLLister {
+	mock := &URLLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -1,43 +1,89 @@
-// Code generated by mockery v2.28.2. DO NOT EDIT.
+// Code generated by mockery v2.53.5. DO NOT EDIT.
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
 
 // URLSaver is an autogenerated mock type for the URLSaver type
 type URLSaver struct {
 	mock.Mock
 }
 
-// SaveURL provides a mock function with given fields: urlToSave, alias
-func (_m *URLSaver) SaveURL(urlToSave string, alias string) error {
-	ret := _m.Called(urlToSave, alias)
+type URLSaver_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLSaver) EXPECT() *URLSaver_Expecter {
+	return &URLSaver_Expecter{mock: &_m.Mock}
+}
+
+// SaveURL provides a mock function with given fields: ctx, urlToSave, originalURL, alias, expiresAt, keyID
+func (_m *URLSaver) SaveURL(ctx context.Context, urlToSave string, originalURL string, alias string, expiresAt time.Time, keyID int64) error {
+	ret := _m.Called(ctx, urlToSave, originalURL, alias, expiresAt, keyID)
 
-	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		return rf(urlToSave, alias)
+	if len(ret) == 0 {
+		panic("no return value specified for SaveURL")
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(urlToSave, alias)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, time.Time, int64) error); ok {
+		r0 = rf(ctx, urlToSave, originalURL, alias, expiresAt, keyID)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r1
+	return r0
 }
 
-type mockConstructorTestingTNewURLSaver interface {
-	mock.TestingT
-	Cleanup(func())
+// URLSaver_SaveURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveURL'
+type URLSaver_SaveURL_Call struct {
+	*mock.Call
+}
+
+// SaveURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - urlToSave string
+//   - originalURL string
+//   - alias string
+//   - expiresAt time.Time
+//   - keyID int64
+func (_e *URLSaver_Expecter) SaveURL(ctx interface{}, urlToSave interface{}, originalURL interface{}, alias interface{}, expiresAt interface{}, keyID interface{}) *URLSaver_SaveURL_Call {
+	return &URLSaver_SaveURL_Call{Call: _e.mock.On("SaveURL", ctx, urlToSave, originalURL, alias, expiresAt, keyID)}
+}
+
+func (_c *URLSaver_SaveURL_Call) Run(run func(ctx context.Context, urlToSave string, originalURL string, alias string, expiresAt time.Time, keyID int64)) *URLSaver_SaveURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(time.Time), args[5].(int64))
+	})
+	return _c
+}
+
+func (_c *URLSaver_SaveURL_Call) Return(_a0 error) *URLSaver_SaveURL_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *URLSaver_SaveURL_Call) RunAndReturn(run func(context.Context, string, string, string, time.Time, int64) error) *URLSaver_SaveURL_Call {
+	_c.Call.Return(run)
+	return _c
 }
 
 // NewURLSaver creates a new instance of URLSaver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-func NewURLSaver(t mockConstructorTestingTNewURLSaver) *URLSaver {
+// The first argument is typically a *testing.T value.
+func NewURLSaver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLSaver {
 	mock := &URLSaver{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
 
 	return mock
-}
\ No newline at end of file
+}
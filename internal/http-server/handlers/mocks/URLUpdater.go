@@ -0,0 +1,87 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	storage "go-url-shortener/internal/storage"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// URLUpdater is an autogenerated mock type for the URLUpdater type
+type URLUpdater struct {
+	mock.Mock
+}
+
+type URLUpdater_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLUpdater) EXPECT() *URLUpdater_Expecter {
+	return &URLUpdater_Expecter{mock: &_m.Mock}
+}
+
+// UpdateURL provides a mock function with given fields: ctx, newURL, originalURL, alias, caller
+func (_m *URLUpdater) UpdateURL(ctx context.Context, newURL string, originalURL string, alias string, caller storage.APIKey) error {
+	ret := _m.Called(ctx, newURL, originalURL, alias, caller)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateURL")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, storage.APIKey) error); ok {
+		r0 = rf(ctx, newURL, originalURL, alias, caller)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// URLUpdater_UpdateURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateURL'
+type URLUpdater_UpdateURL_Call struct {
+	*mock.Call
+}
+
+// UpdateURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - newURL string
+//   - originalURL string
+//   - alias string
+//   - caller storage.APIKey
+func (_e *URLUpdater_Expecter) UpdateURL(ctx interface{}, newURL interface{}, originalURL interface{}, alias interface{}, caller interface{}) *URLUpdater_UpdateURL_Call {
+	return &URLUpdater_UpdateURL_Call{Call: _e.mock.On("UpdateURL", ctx, newURL, originalURL, alias, caller)}
+}
+
+func (_c *URLUpdater_UpdateURL_Call) Run(run func(ctx context.Context, newURL string, originalURL string, alias string, caller storage.APIKey)) *URLUpdater_UpdateURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(storage.APIKey))
+	})
+	return _c
+}
+
+func (_c *URLUpdater_UpdateURL_Call) Return(_a0 error) *URLUpdater_UpdateURL_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *URLUpdater_UpdateURL_Call) RunAndReturn(run func(context.Context, string, string, string, storage.APIKey) error) *URLUpdater_UpdateURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewURLUpdater creates a new instance of URLUpdater. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewURLUpdater(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLUpdater {
+	mock := &URLUpdater{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,50 +7,69 @@ import (
 	"go-url-shortener/internal/storage"
 
 	// embedded
+	"context"
 	"errors"
 	"log/slog"
 	"net/http"
+	"time"
 
 	// external
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/render"
 )
 
 
 //go:generate go run github.com/vektra/mockery/v2@latest --name=URLGetter --output=mocks --outpkg=mocks --with-expecter
 type URLGetter interface {
-	GetURL(alias string) (string, error)
+	GetURL(ctx context.Context, alias string) (string, error)
 }
 
-func New(log *slog.Logger, urlGetter URLGetter) http.HandlerFunc {
+// ClickRecorder must not block, the click is written asynchronously.
+//go:generate go run github.com/vektra/mockery/v2@latest --name=ClickRecorder --output=mocks --outpkg=mocks --with-expecter
+type ClickRecorder interface {
+	Record(click storage.Click)
+}
+
+func New(log *slog.Logger, urlGetter URLGetter, clickRecorder ClickRecorder) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.url.redirect.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.TraceID(r.Context()),
 		)
 
 		alias := chi.URLParam(r, "alias")
 		if alias == "" {
 			log.Info("alias is empty")
-			render.JSON(w, r, response.Error("invalid alias"))
+			response.Render(w, r, http.StatusBadRequest, response.Error("invalid alias"))
 			return
 		}
 
-		resURL, err := urlGetter.GetURL(alias)
-		if errors.Is(err, storage.ErrURLNotFound) {
+		resURL, err := urlGetter.GetURL(r.Context(), alias)
+		switch {
+		case errors.Is(err, storage.ErrURLNotFound):
 			log.Info("url not found", "alias", alias)
-			render.JSON(w, r, response.Error("not found"))
-			return
+		case errors.Is(err, storage.ErrURLExpired):
+			log.Info("url expired", "alias", alias)
+		case err != nil:
+			log.Info("failes to get url", sl.Err(err))
 		}
 		if err != nil {
-			log.Info("failes to get url", sl.Err(err))
-			render.JSON(w, r, response.Error("internal error"))
+			response.RenderError(w, r, err, "failed to get url")
 			return
 		}
 		log.Info("got url", slog.String("url", resURL))
+
+		clickRecorder.Record(storage.Click{
+			Alias:     alias,
+			At:        time.Now(),
+			Referrer:  r.Referer(),
+			UserAgent: r.UserAgent(),
+			RequestID: middleware.GetReqID(r.Context()),
+		})
+
 		http.Redirect(w, r, resURL, http.StatusFound)
 	}
 }
@@ -7,13 +7,19 @@ import (
 	"go-url-shortener/internal/lib/api"
 	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
 	
+	"go-url-shortener/internal/storage"
+	
 	// embedded
+	"encoding/json"
+	"errors"
+	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	// external
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 )
@@ -23,27 +29,67 @@ func TestRedirectHandler(t *testing.T) {
 		name      string
 		alias     string
 		url       string
-		respError string
-		mockError error
+		respError  string
+		mockError  error
+		statusCode int
 	}{
 		{
 			name:  "Success",
 			alias: "test_alias",
 			url:   "https://www.google.com/",
 		},
+		{
+			name:      "Expired",
+			alias:     "expired_alias",
+			respError:  "url expired",
+			mockError:  storage.ErrURLExpired,
+			statusCode: http.StatusGone,
+		},
+		{
+			name:       "Not found",
+			alias:      "missing_alias",
+			respError:  "not found",
+			mockError:  storage.ErrURLNotFound,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Internal error",
+			alias:      "broken_alias",
+			respError:  "failed to get url",
+			mockError:  errors.New("db down"),
+			statusCode: http.StatusInternalServerError,
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			urlGetterMock := mocks.NewURLGetter(t)
+			clickRecorderMock := mocks.NewClickRecorder(t)
 
 			if tc.respError == "" || tc.mockError != nil {
-				urlGetterMock.On("GetURL", tc.alias).
+				urlGetterMock.On("GetURL", mock.Anything, tc.alias).
 					Return(tc.url, tc.mockError).Once()
 			}
+			if tc.respError == "" {
+				clickRecorderMock.On("Record", mock.MatchedBy(func(c storage.Click) bool {
+					return c.Alias == tc.alias && !c.At.IsZero()
+				})).Once()
+			}
 
 			r := chi.NewRouter()
-			r.Get("/{alias}", redirect.New(slogdiscard.NewDiscardLogger(), urlGetterMock))
+			r.Get("/{alias}", redirect.New(slogdiscard.NewDiscardLogger(), urlGetterMock, clickRecorderMock))
+
+			if tc.respError != "" {
+				rec := httptest.NewRecorder()
+				r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/"+tc.alias, nil))
+
+				assert.Equal(t, tc.statusCode, rec.Code)
+
+				var resp map[string]string
+				require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+				assert.Equal(t, tc.respError, resp["error"])
+				return
+			}
 
 			ts := httptest.NewServer(r)
 			defer ts.Close()
@@ -2,15 +2,21 @@ package save
 
 import (
 	// project
+	"go-url-shortener/internal/http-server/middleware/auth"
+	"go-url-shortener/internal/lib/alias"
 	"go-url-shortener/internal/lib/api/response"
 	"go-url-shortener/internal/lib/logger/sl"
-	"go-url-shortener/internal/lib/random"
+	"go-url-shortener/internal/lib/urlnorm"
 	"go-url-shortener/internal/storage"
 
 	// embedded
+	"context"
 	"errors"
 	"log/slog"
 	"net/http"
+	"regexp"
+	"strconv"
+	"time"
 
 	// external
 	"github.com/go-chi/chi/v5/middleware"
@@ -18,32 +24,92 @@ import (
 	"github.com/go-playground/validator/v10"
 )
 
-// TODO: move to config if needed
-const aliasLength = 6
+// maxAliasAttempts bounds how many generated aliases are tried before
+// a collision is reported to the client.
+const maxAliasAttempts = 5
 
+// Request may set either ExpiresAt or TTL (a Go duration, e.g. "72h").
+// Without both the url never expires.
 type Request struct {
-	URL   string `json:"url" validate:"required,url"`
-	Alias string `json:"alias,omitempty"`
+	URL       string     `json:"url" validate:"required,url"`
+	Alias     string     `json:"alias,omitempty" validate:"omitempty,alias"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	TTL       string     `json:"ttl,omitempty"`
+}
+
+// aliasRe is the charset of custom aliases. Slashes would break routing
+// and dots are cut off as a format extension by middleware.URLFormat.
+var aliasRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Options shape aliases: Generator makes them for requests without one,
+// custom ones may be up to MaxCustomLength characters long and must not
+// be Reserved. With Dedupe set, a request without alias and expiry gets
+// the alias the caller already has for the url instead of a new one.
+// Urls are stored normalized with Normalize.
+type Options struct {
+	Generator       AliasGenerator
+	MaxCustomLength int
+	Reserved        *alias.Reserved
+	Dedupe          URLFinder
+	Normalize       urlnorm.Options
 }
 
 type Response struct {
 	response.Response
-	Alias string `json:"alias,omitempty"`
+	Alias     string     `json:"alias,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
+var (
+	errExpiryConflict = errors.New("only one of expires_at and ttl may be set")
+	errInvalidTTL     = errors.New("ttl must be a positive duration, e.g. 72h")
+	errExpiryInPast   = errors.New("expires_at must be in the future")
+	errAliasReserved  = errors.New("generated alias is reserved")
+)
+
 
 //go:generate go run github.com/vektra/mockery/v2@latest --name=URLSaver --output=mocks --outpkg=mocks --with-expecter
 type URLSaver interface {
-	SaveURL(urlToSave string, alias string) error
+	// SaveURL stores the url under alias on behalf of the api key keyID,
+	// originalURL is kept for display. A zero expiresAt means the url
+	// never expires.
+	SaveURL(ctx context.Context, urlToSave string, originalURL string, alias string, expiresAt time.Time, keyID int64) error
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=URLFinder --output=mocks --outpkg=mocks --with-expecter
+type URLFinder interface {
+	// FindURL returns the alias of a never expiring url saved by keyID,
+	// storage.ErrURLNotFound if there is none.
+	FindURL(ctx context.Context, urlToFind string, keyID int64) (string, error)
+}
+
+// AliasGenerator makes aliases for urls saved without a custom one.
+type AliasGenerator interface {
+	NewAlias(ctx context.Context) (string, error)
 }
 
-func New(log *slog.Logger, urlSaver URLSaver) http.HandlerFunc {
+func New(log *slog.Logger, urlSaver URLSaver, opts Options) http.HandlerFunc {
+	validate := validator.New()
+	_ = validate.RegisterValidation("alias", func(fl validator.FieldLevel) bool {
+		return aliasRe.MatchString(fl.Field().String())
+	})
+	validate.RegisterStructValidation(func(sl validator.StructLevel) {
+		req := sl.Current().Interface().(Request)
+		if len(req.Alias) > opts.MaxCustomLength {
+			sl.ReportError(req.Alias, "Alias", "Alias", "max", strconv.Itoa(opts.MaxCustomLength))
+		}
+		if opts.Reserved.Contains(req.Alias) {
+			sl.ReportError(req.Alias, "Alias", "Alias", "reserved", "")
+		}
+	}, Request{})
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.url.save.New"
 
-		log = log.With(
+		log := log.With(
 			slog.String("op", op),
 			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.TraceID(r.Context()),
 		)
 
 		var req Request
@@ -51,43 +117,121 @@ func New(log *slog.Logger, urlSaver URLSaver) http.HandlerFunc {
 		err := render.DecodeJSON(r.Body, &req)
 		if err != nil {
 			log.Error("failed to decode request body", sl.Err(err))
-			render.JSON(w, r, response.Error("failed to decode request"))
+			response.Render(w, r, http.StatusBadRequest, response.Error("failed to decode request"))
 			return
 		}
 
 		log.Info("request body decoded successfully", slog.Any("request", req))
-		if err := validator.New().Struct(req); err != nil {
+		if err := validate.Struct(req); err != nil {
 			validateErr := err.(validator.ValidationErrors)
 			log.Error("invalid request", sl.Err(err))
-			render.JSON(w, r, response.ValidationError(validateErr))
+			response.Render(w, r, http.StatusBadRequest, response.ValidationError(validateErr))
 			return
 		}
 
-		alias := req.Alias
-		if alias == "" {
-			alias = random.NewRandomString(aliasLength)
+		expiresAt, err := expiration(req, time.Now())
+		if err != nil {
+			log.Error("invalid expiration", sl.Err(err))
+			response.Render(w, r, http.StatusBadRequest, response.Error(err.Error()))
+			return
 		}
 
-		err = urlSaver.SaveURL(req.URL, alias)
+		urlToSave, err := urlnorm.Normalize(req.URL, opts.Normalize)
+		if err != nil {
+			log.Info("failed to normalize url", sl.Err(err))
+			response.Render(w, r, http.StatusBadRequest, response.Error("invalid url"))
+			return
+		}
+
+		// the bootstrap admin key and unauthenticated setups have no id
+		key, _ := auth.FromContext(r.Context())
+
+		if opts.Dedupe != nil && req.Alias == "" && expiresAt.IsZero() {
+			existing, err := opts.Dedupe.FindURL(r.Context(), urlToSave, key.ID)
+			if err == nil {
+				log.Info("url already shortened", slog.String("alias", existing))
+				responseOk(w, r, existing, expiresAt)
+				return
+			}
+			if !errors.Is(err, storage.ErrURLNotFound) {
+				log.Error("failed to find url", sl.Err(err))
+				response.RenderError(w, r, err, "failed to save url")
+				return
+			}
+		}
+
+		// a generated alias may collide with an existing one or spell a
+		// reserved word, then another one is tried; a taken custom alias is
+		// reported right away
+		alias := req.Alias
+		generated := alias == ""
+		for attempt := 1; ; attempt++ {
+			if generated {
+				alias, err = opts.Generator.NewAlias(r.Context())
+				if err != nil {
+					break
+				}
+				if opts.Reserved.Contains(alias) {
+					err = errAliasReserved
+					if attempt == maxAliasAttempts {
+						break
+					}
+					log.Info("generated alias is reserved, retrying", slog.String("alias", alias), slog.Int("attempt", attempt))
+					continue
+				}
+			}
+
+			err = urlSaver.SaveURL(r.Context(), urlToSave, req.URL, alias, expiresAt, key.ID)
+			if !generated || !errors.Is(err, storage.ErrURlExists) || attempt == maxAliasAttempts {
+				break
+			}
+			log.Info("generated alias is taken, retrying", slog.String("alias", alias), slog.Int("attempt", attempt))
+		}
 		if errors.Is(err, storage.ErrURlExists) {
 			log.Info("url already exists", slog.String("url", req.URL))
-			render.JSON(w, r, response.Error("url already exists"))
+			response.RenderError(w, r, err, "failed to save url")
 			return
 		}
 		if err != nil {
 			log.Error("failed to save url", sl.Err(err))
-			render.JSON(w, r, response.Error("failed to save url"))
+			response.RenderError(w, r, err, "failed to save url")
 			return
 		}
 
 		log.Info("url added successfully", slog.String("alias", alias))
-		responseOk(w, r, alias)
+		responseOk(w, r, alias, expiresAt)
 	}
 }
 
-func responseOk(w http.ResponseWriter, r *http.Request, alias string) {
-	render.JSON(w, r, Response{
+// expiration resolves the absolute expiry time of the request,
+// the zero time means the url never expires.
+func expiration(req Request, now time.Time) (time.Time, error) {
+	switch {
+	case req.ExpiresAt != nil && req.TTL != "":
+		return time.Time{}, errExpiryConflict
+	case req.ExpiresAt != nil:
+		if !req.ExpiresAt.After(now) {
+			return time.Time{}, errExpiryInPast
+		}
+		return *req.ExpiresAt, nil
+	case req.TTL != "":
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 {
+			return time.Time{}, errInvalidTTL
+		}
+		return now.Add(ttl), nil
+	default:
+		return time.Time{}, nil
+	}
+}
+
+func responseOk(w http.ResponseWriter, r *http.Request, alias string, expiresAt time.Time) {
+	resp := Response{
 		Response: response.OK(),
 		Alias:    alias,
-	})
+	}
+	if !expiresAt.IsZero() {
+		resp.ExpiresAt = &expiresAt
+	}
+	render.JSON(w, r, resp)
 }
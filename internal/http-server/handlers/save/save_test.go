@@ -2,60 +2,161 @@ package save_test
 
 import (
 	// project
-	"go-url-shortener/internal/http-server/handlers/save"
 	"go-url-shortener/internal/http-server/handlers/mocks"
+	"go-url-shortener/internal/http-server/handlers/save"
+	"go-url-shortener/internal/http-server/middleware/auth"
+	"go-url-shortener/internal/lib/alias"
 	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
-	
+	"go-url-shortener/internal/lib/random"
+	"go-url-shortener/internal/storage"
+
 	// embedded
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	// external
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
+var options = save.Options{
+	Generator:       alias.NewRandom(6, random.Lowercase),
+	MaxCustomLength: 16,
+	Reserved:        alias.NewReserved("url", "admin"),
+}
+
 func TestSaveHandler(t *testing.T) {
 	cases := []struct {
-		name      string
-		alias     string
-		url       string
-		respError string
-		mockError error
+		name       string
+		alias      string
+		url        string
+		ttl        string
+		expiresAt  string
+		respError  string
+		mockError  error
+		statusCode int
 	}{
 		{
-			name:  "Success",
-			alias: "test_alias",
-			url:   "https://google.com",
+			name:       "Success",
+			alias:      "test_alias",
+			url:        "https://google.com",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty alias",
+			alias:      "",
+			url:        "https://google.com",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "URL normalized",
+			alias:      "normalized",
+			url:        "HTTPS://Google.COM:443/a/..",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty URL",
+			url:        "",
+			alias:      "some_alias",
+			respError:  "field URL is a required field",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Invalid URL",
+			url:        "some invalid URL",
+			alias:      "some_alias",
+			respError:  "field URL is not a valid URL",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "With TTL",
+			alias:      "ttl_alias",
+			url:        "https://google.com",
+			ttl:        "24h",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "With expires_at",
+			alias:      "expiring_alias",
+			url:        "https://google.com",
+			expiresAt:  time.Now().Add(time.Hour).Format(time.RFC3339),
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Custom alias too long",
+			alias:      "a_very_long_custom_alias",
+			url:        "https://google.com",
+			respError:  "field Alias must be at most 16 characters long",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Custom alias with slash",
+			alias:      "some/alias",
+			url:        "https://google.com",
+			respError:  "field Alias may only contain letters, digits, '-' and '_'",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Custom alias with dot",
+			alias:      "alias.json",
+			url:        "https://google.com",
+			respError:  "field Alias may only contain letters, digits, '-' and '_'",
+			statusCode: http.StatusBadRequest,
 		},
 		{
-			name:  "Empty alias",
-			alias: "",
-			url:   "https://google.com",
+			name:       "Reserved alias",
+			alias:      "Admin",
+			url:        "https://google.com",
+			respError:  "field Alias is reserved",
+			statusCode: http.StatusBadRequest,
 		},
 		{
-			name:      "Empty URL",
-			url:       "",
-			alias:     "some_alias",
-			respError: "field URL is a required field",
+			name:       "Invalid TTL",
+			alias:      "some_alias",
+			url:        "https://google.com",
+			ttl:        "-5m",
+			respError:  "ttl must be a positive duration, e.g. 72h",
+			statusCode: http.StatusBadRequest,
 		},
 		{
-			name:      "Invalid URL",
-			url:       "some invalid URL",
-			alias:     "some_alias",
-			respError: "field URL is not a valid URL",
+			name:       "Expiry in the past",
+			alias:      "some_alias",
+			url:        "https://google.com",
+			expiresAt:  time.Now().Add(-time.Hour).Format(time.RFC3339),
+			respError:  "expires_at must be in the future",
+			statusCode: http.StatusBadRequest,
 		},
 		{
-			name:      "SaveURL Error",
-			alias:     "test_alias",
-			url:       "https://google.com",
-			respError: "failed to save url",
-			mockError: errors.New("unexpected error"),
+			name:       "Both TTL and expires_at",
+			alias:      "some_alias",
+			url:        "https://google.com",
+			ttl:        "1h",
+			expiresAt:  time.Now().Add(time.Hour).Format(time.RFC3339),
+			respError:  "only one of expires_at and ttl may be set",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "URL exists",
+			alias:      "test_alias",
+			url:        "https://google.com",
+			respError:  "url already exists",
+			mockError:  storage.ErrURlExists,
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "SaveURL Error",
+			alias:      "test_alias",
+			url:        "https://google.com",
+			respError:  "failed to save url",
+			mockError:  errors.New("unexpected error"),
+			statusCode: http.StatusInternalServerError,
 		},
 	}
 
@@ -68,22 +169,31 @@ func TestSaveHandler(t *testing.T) {
 			urlSaverMock := mocks.NewURLSaver(t)
 
 			if tc.respError == "" || tc.mockError != nil {
-				urlSaverMock.On("SaveURL", tc.url, mock.AnythingOfType("string")).
-					Return(int64(1), tc.mockError).
+				urlSaverMock.On("SaveURL", mock.Anything, "https://google.com/", tc.url, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), int64(7)).
+					Return(tc.mockError).
 					Once()
 			}
 
-			handler := save.New(slogdiscard.NewDiscardLogger(), urlSaverMock)
+			handler := save.New(slogdiscard.NewDiscardLogger(), urlSaverMock, options)
 
-			input := fmt.Sprintf(`{"url": "%s", "alias": "%s"}`, tc.url, tc.alias)
+			fields := map[string]string{"url": tc.url, "alias": tc.alias}
+			if tc.ttl != "" {
+				fields["ttl"] = tc.ttl
+			}
+			if tc.expiresAt != "" {
+				fields["expires_at"] = tc.expiresAt
+			}
+			input, err := json.Marshal(fields)
+			require.NoError(t, err)
 
-			req, err := http.NewRequest(http.MethodPost, "/save", bytes.NewReader([]byte(input)))
+			req, err := http.NewRequest(http.MethodPost, "/save", bytes.NewReader(input))
 			require.NoError(t, err)
+			req = req.WithContext(auth.WithKey(req.Context(), storage.APIKey{ID: 7, Role: storage.RoleUser}))
 
 			rr := httptest.NewRecorder()
 			handler.ServeHTTP(rr, req)
 
-			require.Equal(t, rr.Code, http.StatusOK)
+			require.Equal(t, tc.statusCode, rr.Code)
 
 			body := rr.Body.String()
 
@@ -96,4 +206,236 @@ func TestSaveHandler(t *testing.T) {
 			// TODO: add more checks
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestSaveHandler_AliasCollision(t *testing.T) {
+	cases := []struct {
+		name       string
+		alias      string
+		collisions int
+		calls      int
+		statusCode int
+	}{
+		{
+			name:       "Generated alias retried",
+			collisions: 2,
+			calls:      3,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Generated alias gives up",
+			collisions: 5,
+			calls:      5,
+			statusCode: http.StatusConflict,
+		},
+		{
+			name:       "Custom alias not retried",
+			alias:      "taken",
+			collisions: 1,
+			calls:      1,
+			statusCode: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			urlSaverMock := mocks.NewURLSaver(t)
+
+			var aliases []string
+			call := urlSaverMock.On("SaveURL", mock.Anything, "https://google.com/", "https://google.com", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), int64(0)).
+				Run(func(args mock.Arguments) { aliases = append(aliases, args.String(3)) })
+			if tc.collisions >= tc.calls {
+				call.Return(storage.ErrURlExists).Times(tc.calls)
+			} else {
+				call.Return(storage.ErrURlExists).Times(tc.collisions)
+				urlSaverMock.On("SaveURL", mock.Anything, "https://google.com/", "https://google.com", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), int64(0)).
+					Run(func(args mock.Arguments) { aliases = append(aliases, args.String(3)) }).
+					Return(nil).
+					Once()
+			}
+
+			handler := save.New(slogdiscard.NewDiscardLogger(), urlSaverMock, options)
+
+			input, err := json.Marshal(map[string]string{"url": "https://google.com", "alias": tc.alias})
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/save", bytes.NewReader(input)))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+			require.Len(t, aliases, tc.calls)
+			if tc.alias == "" {
+				// generated aliases follow the options
+				for _, alias := range aliases {
+					require.Len(t, alias, 6)
+					require.Empty(t, strings.Trim(alias, random.Lowercase))
+				}
+			}
+			if tc.alias == "" && tc.calls > 1 {
+				// a fresh alias on every attempt
+				require.NotEqual(t, aliases[0], aliases[1])
+			}
+		})
+	}
+}
+
+type failingGenerator struct{}
+
+func (failingGenerator) NewAlias(context.Context) (string, error) {
+	return "", errors.New("sequence unavailable")
+}
+
+func TestSaveHandler_GeneratorError(t *testing.T) {
+	urlSaverMock := mocks.NewURLSaver(t)
+
+	handler := save.New(slogdiscard.NewDiscardLogger(), urlSaverMock, save.Options{
+		Generator:       failingGenerator{},
+		MaxCustomLength: 16,
+	})
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/save", strings.NewReader(`{"url":"https://google.com"}`)))
+
+	require.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+// sequenceGenerator hands out aliases in order.
+type sequenceGenerator struct {
+	aliases []string
+}
+
+func (g *sequenceGenerator) NewAlias(context.Context) (string, error) {
+	alias := g.aliases[0]
+	g.aliases = g.aliases[1:]
+	return alias, nil
+}
+
+func TestSaveHandler_GeneratedAliasReserved(t *testing.T) {
+	cases := []struct {
+		name       string
+		aliases    []string
+		saved      string
+		statusCode int
+	}{
+		{
+			// e.g. the counter strategy encodes 218597 as "url"
+			name:       "Regenerated",
+			aliases:    []string{"url", "readyz", "abc"},
+			saved:      "abc",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Gives up",
+			aliases:    []string{"url", "url", "url", "url", "url"},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			urlSaverMock := mocks.NewURLSaver(t)
+			if tc.saved != "" {
+				urlSaverMock.On("SaveURL", mock.Anything, "https://google.com/", "https://google.com", tc.saved, mock.AnythingOfType("time.Time"), int64(0)).
+					Return(nil).
+					Once()
+			}
+
+			handler := save.New(slogdiscard.NewDiscardLogger(), urlSaverMock, save.Options{
+				Generator:       &sequenceGenerator{aliases: tc.aliases},
+				MaxCustomLength: 16,
+				Reserved:        alias.NewReserved("url", "readyz"),
+			})
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/save", strings.NewReader(`{"url":"https://google.com"}`)))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+			if tc.saved != "" {
+				var resp save.Response
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+				require.Equal(t, tc.saved, resp.Alias)
+			}
+		})
+	}
+}
+
+func TestSaveHandler_Dedupe(t *testing.T) {
+	cases := []struct {
+		name       string
+		body       string
+		findAlias  string
+		findError  error
+		save       bool
+		statusCode int
+		respAlias  string
+	}{
+		{
+			name:       "Existing url",
+			body:       `{"url":"https://google.com"}`,
+			findAlias:  "existing",
+			statusCode: http.StatusOK,
+			respAlias:  "existing",
+		},
+		{
+			name:       "New url",
+			body:       `{"url":"https://google.com"}`,
+			findError:  storage.ErrURLNotFound,
+			save:       true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Custom alias skips lookup",
+			body:       `{"url":"https://google.com","alias":"custom"}`,
+			save:       true,
+			statusCode: http.StatusOK,
+			respAlias:  "custom",
+		},
+		{
+			name:       "Expiring url skips lookup",
+			body:       `{"url":"https://google.com","ttl":"1h"}`,
+			save:       true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Lookup error",
+			body:       `{"url":"https://google.com"}`,
+			findError:  errors.New("db down"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			urlSaverMock := mocks.NewURLSaver(t)
+			urlFinderMock := mocks.NewURLFinder(t)
+
+			if tc.findAlias != "" || tc.findError != nil {
+				urlFinderMock.On("FindURL", mock.Anything, "https://google.com/", int64(7)).
+					Return(tc.findAlias, tc.findError).
+					Once()
+			}
+			if tc.save {
+				urlSaverMock.On("SaveURL", mock.Anything, "https://google.com/", "https://google.com", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), int64(7)).
+					Return(nil).
+					Once()
+			}
+
+			opts := options
+			opts.Dedupe = urlFinderMock
+			handler := save.New(slogdiscard.NewDiscardLogger(), urlSaverMock, opts)
+
+			req := httptest.NewRequest(http.MethodPost, "/save", strings.NewReader(tc.body))
+			req = req.WithContext(auth.WithKey(req.Context(), storage.APIKey{ID: 7, Role: storage.RoleUser}))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var resp save.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			if tc.respAlias != "" {
+				require.Equal(t, tc.respAlias, resp.Alias)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package stats
+
+import (
+	// project
+	"go-url-shortener/internal/lib/api/response"
+	"go-url-shortener/internal/lib/logger/sl"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	// external
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultDays = 30
+	maxDays     = 365
+)
+
+type Response struct {
+	response.Response
+	Alias string                `json:"alias,omitempty"`
+	Total int64                 `json:"total"`
+	Daily []storage.DailyClicks `json:"daily"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=StatsGetter --output=mocks --outpkg=mocks --with-expecter
+type StatsGetter interface {
+	GetStats(ctx context.Context, alias string, days int) (storage.Stats, error)
+}
+
+func New(log *slog.Logger, statsGetter StatsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.stats.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.TraceID(r.Context()),
+		)
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.Info("alias is empty")
+			response.Render(w, r, http.StatusBadRequest, response.Error("invalid alias"))
+			return
+		}
+
+		days := defaultDays
+		if raw := r.URL.Query().Get("days"); raw != "" {
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 || n > maxDays {
+				log.Info("invalid days", slog.String("days", raw))
+				response.Render(w, r, http.StatusBadRequest, response.Error("days must be between 1 and 365"))
+				return
+			}
+			days = n
+		}
+
+		stats, err := statsGetter.GetStats(r.Context(), alias, days)
+		switch {
+		case errors.Is(err, storage.ErrURLNotFound):
+			log.Info("url not found", "alias", alias)
+		case err != nil:
+			log.Error("failed to get stats", sl.Err(err))
+		}
+		if err != nil {
+			response.RenderError(w, r, err, "failed to get stats")
+			return
+		}
+
+		daily := stats.Daily
+		if daily == nil {
+			daily = []storage.DailyClicks{}
+		}
+
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Alias:    alias,
+			Total:    stats.Total,
+			Daily:    daily,
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package stats_test
+
+import (
+	// project
+	"go-url-shortener/internal/http-server/handlers/mocks"
+	"go-url-shortener/internal/http-server/handlers/stats"
+	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// external
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsHandler(t *testing.T) {
+	cases := []struct {
+		name      string
+		alias     string
+		query     string
+		days      int
+		stats     storage.Stats
+		mockError error
+		respError string
+	}{
+		{
+			name:  "Success",
+			alias: "test_alias",
+			days:  30,
+			stats: storage.Stats{
+				Total: 3,
+				Daily: []storage.DailyClicks{{Date: "2026-10-17", Clicks: 1}, {Date: "2026-10-18", Clicks: 2}},
+			},
+		},
+		{
+			name:  "Custom days",
+			alias: "test_alias",
+			query: "?days=7",
+			days:  7,
+		},
+		{
+			name:      "Invalid days",
+			alias:     "test_alias",
+			query:     "?days=0",
+			respError: "days must be between 1 and 365",
+		},
+		{
+			name:      "URL Not Found",
+			alias:     "missing",
+			days:      30,
+			mockError: storage.ErrURLNotFound,
+			respError: "not found",
+		},
+		{
+			name:      "Internal Error",
+			alias:     "broken",
+			days:      30,
+			mockError: errors.New("db down"),
+			respError: "failed to get stats",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			statsGetter := mocks.NewStatsGetter(t)
+
+			if tc.days != 0 {
+				statsGetter.On("GetStats", mock.Anything, tc.alias, tc.days).
+					Return(tc.stats, tc.mockError).
+					Once()
+			}
+
+			r := chi.NewRouter()
+			r.Get("/{alias}/stats", stats.New(slogdiscard.NewDiscardLogger(), statsGetter))
+
+			req := httptest.NewRequest(http.MethodGet, "/"+tc.alias+"/stats"+tc.query, nil)
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			var resp stats.Response
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+			if tc.respError == "" {
+				assert.Equal(t, tc.stats.Total, resp.Total)
+				assert.Len(t, resp.Daily, len(tc.stats.Daily))
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package update
+
+import (
+	// project
+	"go-url-shortener/internal/http-server/middleware/auth"
+	"go-url-shortener/internal/lib/api/response"
+	"go-url-shortener/internal/lib/logger/sl"
+	"go-url-shortener/internal/lib/urlnorm"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	// external
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+type Request struct {
+	URL string `json:"url" validate:"required,url"`
+}
+
+type Response struct {
+	response.Response
+	Alias string `json:"alias,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=URLUpdater --output=mocks --outpkg=mocks --with-expecter
+type URLUpdater interface {
+	// UpdateURL points alias to newURL unless caller is neither its owner nor an admin.
+	// originalURL is kept for display.
+	UpdateURL(ctx context.Context, newURL string, originalURL string, alias string, caller storage.APIKey) error
+}
+
+// New updates the target of an alias. Like on save, the url is stored
+// normalized with normalize.
+func New(log *slog.Logger, urlUpdater URLUpdater, normalize urlnorm.Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.url.update.New"
+
+		log := log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+			sl.TraceID(r.Context()),
+		)
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.Info("alias is empty")
+			response.Render(w, r, http.StatusBadRequest, response.Error("invalid alias"))
+			return
+		}
+
+		var req Request
+
+		err := render.DecodeJSON(r.Body, &req)
+		if err != nil {
+			log.Error("failed to decode request body", sl.Err(err))
+			response.Render(w, r, http.StatusBadRequest, response.Error("failed to decode request"))
+			return
+		}
+
+		log.Info("request body decoded successfully", slog.Any("request", req))
+		if err := validator.New().Struct(req); err != nil {
+			validateErr := err.(validator.ValidationErrors)
+			log.Error("invalid request", sl.Err(err))
+			response.Render(w, r, http.StatusBadRequest, response.ValidationError(validateErr))
+			return
+		}
+
+		newURL, err := urlnorm.Normalize(req.URL, normalize)
+		if err != nil {
+			log.Info("failed to normalize url", sl.Err(err))
+			response.Render(w, r, http.StatusBadRequest, response.Error("invalid url"))
+			return
+		}
+
+		// without an identity the caller owns nothing and is refused
+		caller, _ := auth.FromContext(r.Context())
+
+		err = urlUpdater.UpdateURL(r.Context(), newURL, req.URL, alias, caller)
+		switch {
+		case errors.Is(err, storage.ErrURLNotFound):
+			log.Info("url not found", "alias", alias)
+		case errors.Is(err, storage.ErrForbidden):
+			log.Info("url belongs to another owner", "alias", alias, slog.Int64("key_id", caller.ID))
+		case err != nil:
+			log.Error("failed to update url", sl.Err(err))
+		}
+		if err != nil {
+			response.RenderError(w, r, err, "failed to update url")
+			return
+		}
+
+		log.Info("url updated successfully", slog.String("alias", alias))
+		render.JSON(w, r, Response{
+			Response: response.OK(),
+			Alias:    alias,
+			URL:      req.URL,
+		})
+	}
+}
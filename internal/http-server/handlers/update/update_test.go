@@ -0,0 +1,122 @@
+package update_test
+
+import (
+	// project
+	"go-url-shortener/internal/http-server/handlers/mocks"
+	"go-url-shortener/internal/http-server/handlers/update"
+	"go-url-shortener/internal/http-server/middleware/auth"
+	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
+	"go-url-shortener/internal/lib/urlnorm"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// external
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		alias      string
+		url        string
+		stored     string // the normalized url, url if empty
+		mockError  error
+		statusCode int
+		respError  string
+	}{
+		{
+			name:       "Success",
+			alias:      "test_alias",
+			url:        "https://google.com/",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Normalized",
+			alias:      "test_alias",
+			url:        "HTTPS://Example.com:443/a/../b",
+			stored:     "https://example.com/b",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid URL",
+			alias:      "test_alias",
+			url:        "some invalid URL",
+			statusCode: http.StatusBadRequest,
+			respError:  "field URL is not a valid URL",
+		},
+		{
+			name:       "URL Not Found",
+			alias:      "missing",
+			url:        "https://google.com/",
+			mockError:  storage.ErrURLNotFound,
+			statusCode: http.StatusNotFound,
+			respError:  "not found",
+		},
+		{
+			name:       "Foreign URL",
+			alias:      "foreign",
+			url:        "https://google.com/",
+			mockError:  storage.ErrForbidden,
+			statusCode: http.StatusForbidden,
+			respError:  "forbidden",
+		},
+		{
+			name:       "UpdateURL Error",
+			alias:      "broken",
+			url:        "https://google.com/",
+			mockError:  errors.New("db down"),
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to update url",
+		},
+	}
+
+	caller := storage.APIKey{ID: 7, Role: storage.RoleUser}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			updater := mocks.NewURLUpdater(t)
+
+			stored := tc.stored
+			if stored == "" {
+				stored = tc.url
+			}
+			if tc.respError == "" || tc.mockError != nil {
+				updater.On("UpdateURL", mock.Anything, stored, tc.url, tc.alias, caller).
+					Return(tc.mockError).
+					Once()
+			}
+
+			r := chi.NewRouter()
+			r.Put("/{alias}", update.New(slogdiscard.NewDiscardLogger(), updater, urlnorm.Options{}))
+
+			input, err := json.Marshal(map[string]string{"url": tc.url})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPut, "/"+tc.alias, bytes.NewReader(input))
+			req = req.WithContext(auth.WithKey(req.Context(), caller))
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.statusCode, rec.Code)
+
+			var resp update.Response
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+
+			assert.Equal(t, tc.respError, resp.Error)
+			if tc.respError == "" {
+				assert.Equal(t, tc.url, resp.URL)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	// project
+	"go-url-shortener/internal/lib/api/response"
+	"go-url-shortener/internal/lib/apikey"
+	"go-url-shortener/internal/lib/logger/sl"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	// external
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type ctxKey struct{}
+
+type APIKeyGetter interface {
+	// GetAPIKey returns the active key with the given hash.
+	GetAPIKey(ctx context.Context, keyHash string) (storage.APIKey, error)
+}
+
+// New authenticates requests by the "Authorization: Bearer <key>" header and
+// stores the caller's key in the request context. adminKey is a bootstrap
+// key from the config that is accepted with the admin role without being
+// stored, so the first real keys can be created.
+func New(log *slog.Logger, keyGetter APIKeyGetter, adminKey string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/auth"),
+		)
+
+		adminHash := apikey.Hash(adminKey)
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			log := log.With(
+				slog.String("request_id", middleware.GetReqID(r.Context())),
+				sl.TraceID(r.Context()),
+			)
+
+			token, ok := bearerToken(r)
+			if !ok {
+				log.Info("missing api key")
+				unauthorized(w, r)
+				return
+			}
+
+			hash := apikey.Hash(token)
+
+			var key storage.APIKey
+			if adminKey != "" && subtle.ConstantTimeCompare([]byte(hash), []byte(adminHash)) == 1 {
+				key = storage.APIKey{Name: "admin", Role: storage.RoleAdmin}
+			} else {
+				var err error
+				key, err = keyGetter.GetAPIKey(r.Context(), hash)
+				if errors.Is(err, storage.ErrAPIKeyNotFound) {
+					log.Info("unknown or revoked api key")
+					unauthorized(w, r)
+					return
+				}
+				if err != nil {
+					log.Error("failed to get api key", sl.Err(err))
+					response.Render(w, r, http.StatusInternalServerError, response.Error("internal error"))
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithKey(r.Context(), key)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// RequireAdmin rejects callers authenticated by New without the admin role.
+func RequireAdmin(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		key, ok := FromContext(r.Context())
+		if !ok || key.Role != storage.RoleAdmin {
+			response.Render(w, r, http.StatusForbidden, response.Error("forbidden"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// FromContext returns the key the request was authenticated with.
+// The bootstrap admin key has ID 0.
+func FromContext(ctx context.Context) (storage.APIKey, bool) {
+	key, ok := ctx.Value(ctxKey{}).(storage.APIKey)
+	return key, ok
+}
+
+// WithKey returns a copy of ctx carrying key, as New does.
+func WithKey(ctx context.Context, key storage.APIKey) context.Context {
+	return context.WithValue(ctx, ctxKey{}, key)
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="url-shortener"`)
+	response.Render(w, r, http.StatusUnauthorized, response.Error("unauthorized"))
+}
@@ -0,0 +1,85 @@
+package auth_test
+
+import (
+	// project
+	"go-url-shortener/internal/http-server/middleware/auth"
+	"go-url-shortener/internal/lib/apikey"
+	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// external
+	"github.com/stretchr/testify/assert"
+)
+
+const adminKey = "us_admin"
+
+type keyGetter map[string]storage.APIKey
+
+func (g keyGetter) GetAPIKey(ctx context.Context, keyHash string) (storage.APIKey, error) {
+	if keyHash == apikey.Hash("us_broken") {
+		return storage.APIKey{}, errors.New("db down")
+	}
+	key, ok := g[keyHash]
+	if !ok {
+		return storage.APIKey{}, storage.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+func TestAuth(t *testing.T) {
+	keys := keyGetter{
+		apikey.Hash("us_user"): {ID: 7, Name: "ci", Role: storage.RoleUser},
+	}
+
+	cases := []struct {
+		name       string
+		header     string
+		admin      bool
+		statusCode int
+		keyID      int64
+	}{
+		{name: "Valid key", header: "Bearer us_user", statusCode: http.StatusOK, keyID: 7},
+		{name: "Lowercase scheme", header: "bearer us_user", statusCode: http.StatusOK, keyID: 7},
+		{name: "Bootstrap admin key", header: "Bearer " + adminKey, statusCode: http.StatusOK},
+		{name: "Missing header", statusCode: http.StatusUnauthorized},
+		{name: "Basic scheme", header: "Basic bXl1c2VyOm15cGFzcw==", statusCode: http.StatusUnauthorized},
+		{name: "Unknown key", header: "Bearer us_unknown", statusCode: http.StatusUnauthorized},
+		{name: "Storage error", header: "Bearer us_broken", statusCode: http.StatusInternalServerError},
+		{name: "Admin route with user key", header: "Bearer us_user", admin: true, statusCode: http.StatusForbidden},
+		{name: "Admin route with admin key", header: "Bearer " + adminKey, admin: true, statusCode: http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				key, ok := auth.FromContext(r.Context())
+				assert.True(t, ok)
+				assert.Equal(t, tc.keyID, key.ID)
+			})
+			if tc.admin {
+				handler = auth.RequireAdmin(handler)
+			}
+			handler = auth.New(slogdiscard.NewDiscardLogger(), keys, adminKey)(handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/url", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.statusCode, rr.Code)
+			if tc.statusCode == http.StatusUnauthorized {
+				assert.NotEmpty(t, rr.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
@@ -1,16 +1,22 @@
 package logger
 
 import (
+	// project
+	"go-url-shortener/internal/lib/logger/sl"
+
 	// embedded
 	"net/http"
 	"time"
 	"log/slog"
+	"slices"
 
 	// external
 	"github.com/go-chi/chi/v5/middleware"
 )
 
-func New(log *slog.Logger) func(next http.Handler) http.Handler {
+// New logs every completed request except those to skipPaths, e.g. health
+// probes that would otherwise drown the log.
+func New(log *slog.Logger, skipPaths ...string) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		log := log.With(
 			slog.String("component", "middleware/logger"),
@@ -19,12 +25,18 @@ func New(log *slog.Logger) func(next http.Handler) http.Handler {
 		log.Info("logger middleware enabled")
 
 		fn := func(w http.ResponseWriter, r *http.Request) {
+			if slices.Contains(skipPaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			entry := log.With(
 				slog.String("method", r.Method),
 				slog.String("path", r.URL.Path),
 				slog.String("remote_addr", r.RemoteAddr),
 				slog.String("user_agent", r.UserAgent()),
 				slog.String("request_id", middleware.GetReqID(r.Context())),
+				sl.TraceID(r.Context()),
 			)
 			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
 
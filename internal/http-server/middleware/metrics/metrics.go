@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	// embedded
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	// external
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute labels requests no route matched, so that scans for
+// random paths don't add a series per path.
+const unmatchedRoute = "unmatched"
+
+// New counts requests and observes their latency by method, chi route
+// pattern and status. It must be used on the root router, the pattern is
+// complete only once the request went through all sub routers.
+func New(log *slog.Logger, reg prometheus.Registerer) func(next http.Handler) http.Handler {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of handled http requests.",
+	}, []string{"method", "route", "status"})
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of handled http requests.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	reg.MustRegister(requests, duration)
+
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/metrics"),
+		)
+
+		log.Info("metrics middleware enabled")
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			t1 := time.Now()
+			defer func() {
+				route := unmatchedRoute
+				if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+					route = rctx.RoutePattern()
+				}
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+
+				labels := prometheus.Labels{
+					"method": r.Method,
+					"route":  route,
+					"status": strconv.Itoa(status),
+				}
+				requests.With(labels).Inc()
+				duration.With(labels).Observe(time.Since(t1).Seconds())
+			}()
+
+			next.ServeHTTP(ww, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
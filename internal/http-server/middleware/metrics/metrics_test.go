@@ -0,0 +1,48 @@
+package metrics_test
+
+import (
+	// project
+	"go-url-shortener/internal/http-server/middleware/metrics"
+	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
+
+	// embedded
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	// external
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_LabelsByRoutePattern(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	router := chi.NewRouter()
+	router.Use(metrics.New(slogdiscard.NewDiscardLogger(), reg))
+	router.Route("/url", func(r chi.Router) {
+		r.Get("/{alias}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+	})
+	router.Get("/{alias}", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://example.com", http.StatusFound)
+	})
+
+	for _, path := range []string{"/a", "/b", "/url/a", "/no/such/route"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	expected := `
+# HELP http_requests_total Number of handled http requests.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",route="/url/{alias}",status="404"} 1
+http_requests_total{method="GET",route="/{alias}",status="302"} 2
+http_requests_total{method="GET",route="unmatched",status="404"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "http_requests_total"))
+	require.Equal(t, 3, testutil.CollectAndCount(reg, "http_request_duration_seconds"))
+}
@@ -0,0 +1,222 @@
+package ratelimit
+
+import (
+	// project
+	"go-url-shortener/internal/http-server/middleware/auth"
+	"go-url-shortener/internal/lib/api/response"
+	"go-url-shortener/internal/lib/logger/sl"
+
+	// embedded
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	// external
+	"github.com/go-chi/chi/v5/middleware"
+	"golang.org/x/time/rate"
+)
+
+// idleTTL is how long the bucket of a silent client is kept around.
+const idleTTL = 10 * time.Minute
+
+type client struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Limiter keeps one token bucket per client.
+type Limiter struct {
+	rps   rate.Limit
+	burst int
+
+	mu        sync.Mutex
+	clients   map[string]*client
+	lastSweep time.Time
+}
+
+func NewLimiter(rps float64, burst int) *Limiter {
+	return &Limiter{
+		rps:     rate.Limit(rps),
+		burst:   burst,
+		clients: make(map[string]*client),
+	}
+}
+
+// Allow takes a token from the bucket of key. When the bucket is empty it
+// returns false and how long to wait until the next token.
+func (l *Limiter) Allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c := l.client(key, now)
+
+	res := c.limiter.ReserveN(now, 1)
+	if delay := res.DelayFrom(now); delay > 0 {
+		// not taking the token, the request is rejected
+		res.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// Blocked reports whether the bucket of key is empty without taking a
+// token. When it is, it also returns how long to wait until the next token.
+func (l *Limiter) Blocked(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c, ok := l.clients[key]
+	if !ok {
+		return false, 0
+	}
+	tokens := c.limiter.TokensAt(now)
+	if tokens >= 1 {
+		return false, 0
+	}
+	return true, time.Duration((1 - tokens) / float64(l.rps) * float64(time.Second))
+}
+
+// Take takes a token from the bucket of key, if there is one left.
+func (l *Limiter) Take(key string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.client(key, now).limiter.AllowN(now, 1)
+}
+
+// client returns the bucket of key, creating it if needed.
+func (l *Limiter) client(key string, now time.Time) *client {
+	l.sweep(now)
+
+	c, ok := l.clients[key]
+	if !ok {
+		c = &client{limiter: rate.NewLimiter(l.rps, l.burst)}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	return c
+}
+
+// sweep forgets clients idle for longer than idleTTL, at most once per idleTTL.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleTTL {
+		return
+	}
+	l.lastSweep = now
+	for key, c := range l.clients {
+		if now.Sub(c.lastSeen) > idleTTL {
+			delete(l.clients, key)
+		}
+	}
+}
+
+// New limits requests to rps per second with bursts of up to burst requests
+// per client. Clients authenticated by auth.New are told apart by their api
+// key, anyone else by IP. A non-positive rps disables the limit.
+func New(log *slog.Logger, name string, rps float64, burst int) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if rps <= 0 {
+			return next
+		}
+
+		log := log.With(
+			slog.String("component", "middleware/ratelimit"),
+			slog.String("limit", name),
+		)
+
+		log.Info("rate limit enabled", slog.Float64("rps", rps), slog.Int("burst", burst))
+
+		limiter := NewLimiter(rps, burst)
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key := clientKey(r)
+
+			ok, retryAfter := limiter.Allow(key, time.Now())
+			if !ok {
+				log.Warn("rate limit exceeded",
+					slog.String("client", key),
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+					sl.TraceID(r.Context()),
+				)
+				tooManyRequests(w, r, retryAfter)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// FailedAuth limits failed authentications, i.e. 401 responses, to rps per
+// second with bursts of up to burst per client IP. It goes in front of
+// auth.New: an IP out of tokens is rejected before its key is looked up, so
+// neither guessing keys nor flooding the storage with bad ones pays off.
+// The budget is shared by every route the middleware is used on. A
+// non-positive rps disables the limit.
+func FailedAuth(log *slog.Logger, rps float64, burst int) func(next http.Handler) http.Handler {
+	log = log.With(
+		slog.String("component", "middleware/ratelimit"),
+		slog.String("limit", "failed_auth"),
+	)
+
+	if rps > 0 {
+		log.Info("rate limit enabled", slog.Float64("rps", rps), slog.Int("burst", burst))
+	}
+
+	limiter := NewLimiter(rps, burst)
+
+	return func(next http.Handler) http.Handler {
+		if rps <= 0 {
+			return next
+		}
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key := clientIP(r)
+
+			if blocked, retryAfter := limiter.Blocked(key, time.Now()); blocked {
+				log.Warn("too many failed authentications",
+					slog.String("client", key),
+					slog.String("request_id", middleware.GetReqID(r.Context())),
+					sl.TraceID(r.Context()),
+				)
+				tooManyRequests(w, r, retryAfter)
+				return
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			if ww.Status() == http.StatusUnauthorized {
+				limiter.Take(key, time.Now())
+			}
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+func tooManyRequests(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	response.Render(w, r, http.StatusTooManyRequests, response.Error("too many requests"))
+}
+
+func clientKey(r *http.Request) string {
+	if key, ok := auth.FromContext(r.Context()); ok {
+		return "key:" + strconv.FormatInt(key.ID, 10)
+	}
+	return clientIP(r)
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
@@ -0,0 +1,124 @@
+package ratelimit_test
+
+import (
+	// project
+	"go-url-shortener/internal/http-server/middleware/auth"
+	"go-url-shortener/internal/http-server/middleware/ratelimit"
+	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	// external
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	l := ratelimit.NewLimiter(1, 2)
+	now := time.Now()
+
+	ok, _ := l.Allow("a", now)
+	assert.True(t, ok)
+	ok, _ = l.Allow("a", now)
+	assert.True(t, ok)
+
+	ok, retryAfter := l.Allow("a", now)
+	assert.False(t, ok)
+	assert.InDelta(t, time.Second, retryAfter, float64(10*time.Millisecond))
+
+	// buckets are per client
+	ok, _ = l.Allow("b", now)
+	assert.True(t, ok)
+
+	// a rejected request doesn't take a token
+	ok, _ = l.Allow("a", now.Add(time.Second))
+	assert.True(t, ok)
+}
+
+func TestRateLimit(t *testing.T) {
+	handler := ratelimit.New(slogdiscard.NewDiscardLogger(), "test", 1, 1)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	do := func(remoteAddr string, key *storage.APIKey) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		if key != nil {
+			req = req.WithContext(auth.WithKey(req.Context(), *key))
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	assert.Equal(t, http.StatusOK, do("10.0.0.1:1000", nil).Code)
+
+	// same ip, other port
+	rr := do("10.0.0.1:2000", nil)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, do("10.0.0.2:1000", nil).Code)
+
+	// api keys are limited on their own, whatever the ip
+	key := &storage.APIKey{ID: 5}
+	assert.Equal(t, http.StatusOK, do("10.0.0.1:1000", key).Code)
+	assert.Equal(t, http.StatusTooManyRequests, do("10.0.0.3:1000", key).Code)
+}
+
+func TestRateLimit_Disabled(t *testing.T) {
+	handler := ratelimit.New(slogdiscard.NewDiscardLogger(), "test", 0, 0)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	for range 10 {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusOK, rr.Code)
+	}
+}
+
+func TestFailedAuth(t *testing.T) {
+	var calls int
+	handler := ratelimit.FailedAuth(slogdiscard.NewDiscardLogger(), 0.01, 3)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if r.Header.Get("Authorization") != "Bearer good" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}),
+	)
+
+	do := func(remoteAddr string, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Authorization", authorization)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// successful requests don't count
+	for range 5 {
+		assert.Equal(t, http.StatusOK, do("10.0.0.1:1000", "Bearer good").Code)
+	}
+
+	for range 3 {
+		assert.Equal(t, http.StatusUnauthorized, do("10.0.0.1:1000", "Bearer bad").Code)
+	}
+	assert.Equal(t, 8, calls)
+
+	// out of tokens, the key isn't even looked at
+	rr := do("10.0.0.1:2000", "Bearer bad")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "100", rr.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusTooManyRequests, do("10.0.0.1:1000", "Bearer good").Code)
+	assert.Equal(t, 8, calls)
+
+	// other ips are not affected
+	assert.Equal(t, http.StatusUnauthorized, do("10.0.0.2:1000", "Bearer bad").Code)
+}
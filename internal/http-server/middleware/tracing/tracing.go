@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	// embedded
+	"log/slog"
+	"net/http"
+
+	// external
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "go-url-shortener/internal/http-server"
+
+// New starts a server span per request, continuing the caller's trace if
+// it sent one. The span is named by the chi route pattern once routing is
+// done, so it must be used on the root router. The span is carried in the
+// request context, where sl.TraceID picks it up for the logs.
+func New(log *slog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/tracing"),
+		)
+
+		log.Info("tracing middleware enabled")
+
+		tracer := otel.Tracer(tracerName)
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracer.Start(ctx, r.Method,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(r.Method),
+					semconv.URLPath(r.URL.Path),
+					semconv.UserAgentOriginal(r.UserAgent()),
+				),
+			)
+			defer span.End()
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+				route := rctx.RoutePattern()
+				span.SetName(r.Method + " " + route)
+				span.SetAttributes(semconv.HTTPRoute(route))
+			}
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,70 @@
+package tracing_test
+
+import (
+	// project
+	"go-url-shortener/internal/http-server/middleware/tracing"
+	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
+	"go-url-shortener/internal/lib/logger/sl"
+
+	// embedded
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// external
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestTracing(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	var requestID, traceIDAttr string
+	router := chi.NewRouter()
+	router.Use(middleware.RequestID)
+	router.Use(tracing.New(slogdiscard.NewDiscardLogger()))
+	router.Route("/url", func(r chi.Router) {
+		r.Get("/{alias}", func(w http.ResponseWriter, r *http.Request) {
+			requestID = middleware.GetReqID(r.Context())
+			traceIDAttr = sl.TraceID(r.Context()).Value.String()
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+	})
+
+	// the caller's trace is continued
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/url/abc", nil)
+	req.Header.Set(middleware.RequestIDHeader, "caller-request")
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	span := ended[0]
+
+	assert.Equal(t, "GET /url/{alias}", span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, traceID, span.SpanContext().TraceID().String())
+	assert.Equal(t, "caller-request", requestID)
+	assert.Equal(t, traceID, traceIDAttr)
+	assert.Contains(t, span.Attributes(), semconv.HTTPRoute("/url/{alias}"))
+	assert.Contains(t, span.Attributes(), semconv.HTTPResponseStatusCode(http.StatusInternalServerError))
+	assert.Equal(t, codes.Error, span.Status().Code)
+}
@@ -0,0 +1,55 @@
+package alias
+
+import (
+	// project
+	"go-url-shortener/internal/lib/random"
+
+	// embedded
+	"context"
+	"fmt"
+)
+
+// Random makes aliases of random chars.
+type Random struct {
+	length   int
+	alphabet string
+}
+
+func NewRandom(length int, alphabet string) *Random {
+	return &Random{length: length, alphabet: alphabet}
+}
+
+func (g *Random) NewAlias(ctx context.Context) (string, error) {
+	return random.NewString(g.length, g.alphabet), nil
+}
+
+type Sequence interface {
+	// NextAliasID returns the next value of the alias sequence.
+	NextAliasID(ctx context.Context) (int64, error)
+}
+
+// Counter makes aliases by encoding consecutive ids to base62. They stay
+// short as the table grows and never collide with each other.
+type Counter struct {
+	seq Sequence
+	key uint64
+}
+
+// NewCounter returns a counter over seq. A non-zero key obfuscates the ids
+// with Permute, so that aliases can't be guessed in order.
+func NewCounter(seq Sequence, key uint64) *Counter {
+	return &Counter{seq: seq, key: key}
+}
+
+func (g *Counter) NewAlias(ctx context.Context) (string, error) {
+	id, err := g.seq.NextAliasID(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+
+	n := uint64(id)
+	if g.key != 0 {
+		n = Permute(n, g.key)
+	}
+	return Encode(n), nil
+}
@@ -0,0 +1,94 @@
+package alias
+
+import (
+	// embedded
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	// external
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want string
+	}{
+		{n: 0, want: "0"},
+		{n: 9, want: "9"},
+		{n: 10, want: "A"},
+		{n: 61, want: "z"},
+		{n: 62, want: "10"},
+		{n: 3843, want: "zz"},
+		{n: math.MaxUint64, want: "LygHa16AHYF"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Encode(tt.n))
+	}
+}
+
+func TestPermute(t *testing.T) {
+	const key = 0xC0FFEE
+
+	seen := make(map[uint64]bool)
+	for n := uint64(1); n <= 10000; n++ {
+		p := Permute(n, key)
+		assert.Equal(t, n, Unpermute(p, key))
+		assert.Less(t, p, uint64(1)<<permBits)
+		assert.LessOrEqual(t, len(Encode(p)), 6)
+		assert.False(t, seen[p], "duplicate for %d", n)
+		seen[p] = true
+	}
+
+	// high bits are kept, so the mapping is a bijection on every uint64
+	big := uint64(1)<<40 + 12345
+	assert.Equal(t, big, Unpermute(Permute(big, key), key))
+	assert.Equal(t, big&^permMask, Permute(big, key)&^permMask)
+
+	// consecutive ids don't give consecutive codes, other keys other codes
+	assert.NotEqual(t, Permute(1, key)+1, Permute(2, key))
+	assert.NotEqual(t, Permute(1, key), Permute(1, key+1))
+}
+
+type sequence struct {
+	last int64
+	err  error
+}
+
+func (s *sequence) NextAliasID(ctx context.Context) (int64, error) {
+	s.last++
+	return s.last, s.err
+}
+
+func TestCounter(t *testing.T) {
+	plain := NewCounter(&sequence{last: 61}, 0)
+	alias, err := plain.NewAlias(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "10", alias)
+
+	obfuscated := NewCounter(&sequence{last: 61}, 42)
+	alias, err = obfuscated.NewAlias(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Encode(Permute(62, 42)), alias)
+
+	_, err = NewCounter(&sequence{err: errors.New("db down")}, 0).NewAlias(context.Background())
+	assert.Error(t, err)
+}
+
+func TestReserved(t *testing.T) {
+	r := NewReserved("admin", " Health ", "")
+	r.Add("url")
+
+	assert.True(t, r.Contains("admin"))
+	assert.True(t, r.Contains("ADMIN"))
+	assert.True(t, r.Contains("health"))
+	assert.True(t, r.Contains("url"))
+	assert.False(t, r.Contains(""))
+	assert.False(t, r.Contains("urls"))
+
+	var none *Reserved
+	assert.False(t, none.Contains("admin"))
+}
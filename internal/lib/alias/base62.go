@@ -0,0 +1,19 @@
+package alias
+
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Encode returns n in base62, the shortest form without leading zeros.
+func Encode(n uint64) string {
+	if n == 0 {
+		return base62[:1]
+	}
+
+	var b [11]byte // 62^11 > 2^64
+	i := len(b)
+	for n > 0 {
+		i--
+		b[i] = base62[n%62]
+		n /= 62
+	}
+	return string(b[i:])
+}
@@ -0,0 +1,43 @@
+package alias
+
+// Ids are obfuscated with a Feistel network over their low permBits bits,
+// higher bits are kept as is. Ids below 2^34 stay below 2^34 and so encode
+// to at most 6 base62 chars.
+const (
+	permBits = 34
+	halfBits = permBits / 2
+	halfMask = 1<<halfBits - 1
+	permMask = 1<<permBits - 1
+	rounds   = 4
+)
+
+// Permute maps n to another number in a way that can be undone with
+// Unpermute and the same key. Consecutive numbers map to unrelated ones.
+func Permute(n uint64, key uint64) uint64 {
+	l, r := n>>halfBits&halfMask, n&halfMask
+	for i := range rounds {
+		l, r = r, l^feistel(r, key, i)
+	}
+	return n&^permMask | l<<halfBits | r
+}
+
+// Unpermute reverts Permute.
+func Unpermute(n uint64, key uint64) uint64 {
+	l, r := n>>halfBits&halfMask, n&halfMask
+	for i := rounds - 1; i >= 0; i-- {
+		l, r = r^feistel(l, key, i), l
+	}
+	return n&^permMask | l<<halfBits | r
+}
+
+// feistel is the round function, a splitmix64 finalizer over the half,
+// the key and the round number.
+func feistel(half uint64, key uint64, round int) uint64 {
+	x := half + key + uint64(round+1)*0x9E3779B97F4A7C15
+	x ^= x >> 30
+	x *= 0xBF58476D1CE4E5B9
+	x ^= x >> 27
+	x *= 0x94D049BB133111EB
+	x ^= x >> 31
+	return x & halfMask
+}
@@ -0,0 +1,36 @@
+package alias
+
+import (
+	// embedded
+	"strings"
+)
+
+// Reserved is a set of words that can't be used as custom aliases, mostly
+// because they would shadow routes. Words are matched case-insensitively.
+// Fill it before serving, it is read concurrently afterwards.
+type Reserved struct {
+	words map[string]struct{}
+}
+
+func NewReserved(words ...string) *Reserved {
+	r := &Reserved{words: make(map[string]struct{})}
+	r.Add(words...)
+	return r
+}
+
+func (r *Reserved) Add(words ...string) {
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w != "" {
+			r.words[strings.ToLower(w)] = struct{}{}
+		}
+	}
+}
+
+// Contains reports whether alias is reserved. A nil set reserves nothing.
+func (r *Reserved) Contains(alias string) bool {
+	if r == nil {
+		return false
+	}
+	_, ok := r.words[strings.ToLower(alias)]
+	return ok
+}
@@ -2,15 +2,31 @@ package api
 
 import (
 	// embedded
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 var (
 	ErrInvalidStatusCode = errors.New("invalid status code")
 )
 
+// URLInfo is the body returned by GET /url/{alias}.
+type URLInfo struct {
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	Alias       string     `json:"alias"`
+	URL         string     `json:"url"`
+	OriginalURL string     `json:"original_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Expired     bool       `json:"expired"`
+	Clicks      int64      `json:"clicks"`
+	OwnerID     int64      `json:"owner_id,omitempty"`
+}
+
 // GetRedirect returns the final URL after redirection.
 func GetRedirect(url string) (string, error) {
 	const op = "api.GetRedirect"
@@ -32,4 +48,37 @@ func GetRedirect(url string) (string, error) {
 	}
 
 	return resp.Header.Get("Location"), nil
-}
\ No newline at end of file
+}
+
+// GetInfo is the companion of GetRedirect that learns the target of an
+// alias without following it. url points to the info endpoint
+// (.../url/{alias}), authorization is sent as is when not empty.
+func GetInfo(url string, authorization string) (URLInfo, error) {
+	const op = "api.GetInfo"
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return URLInfo{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return URLInfo{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// error responses carry their reason in the body, if it decodes
+	var info URLInfo
+	decodeErr := json.NewDecoder(resp.Body).Decode(&info)
+	if resp.StatusCode != http.StatusOK {
+		return URLInfo{}, fmt.Errorf("%s: %w: %d: %s", op, ErrInvalidStatusCode, resp.StatusCode, info.Error)
+	}
+	if decodeErr != nil {
+		return URLInfo{}, fmt.Errorf("%s: %w", op, decodeErr)
+	}
+
+	return info, nil
+}
@@ -0,0 +1,57 @@
+package response
+
+import (
+	// project
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"errors"
+	"net/http"
+
+	// external
+	"github.com/go-chi/render"
+)
+
+// StatusCode maps err to an HTTP status code. Storage sentinel errors get
+// their dedicated codes, anything else is an internal error.
+func StatusCode(err error) int {
+	switch {
+	case errors.Is(err, storage.ErrURLNotFound), errors.Is(err, storage.ErrAPIKeyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, storage.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, storage.ErrURlExists):
+		return http.StatusConflict
+	case errors.Is(err, storage.ErrURLExpired):
+		return http.StatusGone
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Render writes v as JSON with the given status code.
+func Render(w http.ResponseWriter, r *http.Request, status int, v any) {
+	render.Status(r, status)
+	render.JSON(w, r, v)
+}
+
+// RenderError writes an error response with the status code mapped from err.
+// Storage sentinel errors carry their own message, any other error is
+// reported with fallback so internals don't leak to clients.
+func RenderError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	status := StatusCode(err)
+
+	msg := fallback
+	switch status {
+	case http.StatusNotFound:
+		msg = "not found"
+	case http.StatusForbidden:
+		msg = "forbidden"
+	case http.StatusConflict:
+		msg = "url already exists"
+	case http.StatusGone:
+		msg = "url expired"
+	}
+
+	Render(w, r, status, Error(msg))
+}
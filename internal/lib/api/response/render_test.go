@@ -0,0 +1,81 @@
+package response_test
+
+import (
+	// project
+	"go-url-shortener/internal/lib/api/response"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	// external
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderError(t *testing.T) {
+	cases := []struct {
+		name       string
+		err        error
+		statusCode int
+		respError  string
+	}{
+		{
+			name:       "Not found",
+			err:        fmt.Errorf("op: %w", storage.ErrURLNotFound),
+			statusCode: http.StatusNotFound,
+			respError:  "not found",
+		},
+		{
+			name:       "API key not found",
+			err:        fmt.Errorf("op: %w", storage.ErrAPIKeyNotFound),
+			statusCode: http.StatusNotFound,
+			respError:  "not found",
+		},
+		{
+			name:       "Forbidden",
+			err:        fmt.Errorf("op: %w", storage.ErrForbidden),
+			statusCode: http.StatusForbidden,
+			respError:  "forbidden",
+		},
+		{
+			name:       "Exists",
+			err:        storage.ErrURlExists,
+			statusCode: http.StatusConflict,
+			respError:  "url already exists",
+		},
+		{
+			name:       "Expired",
+			err:        storage.ErrURLExpired,
+			statusCode: http.StatusGone,
+			respError:  "url expired",
+		},
+		{
+			name:       "Unknown",
+			err:        errors.New("connection refused"),
+			statusCode: http.StatusInternalServerError,
+			respError:  "something went wrong",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			response.RenderError(rec, req, tc.err, "something went wrong")
+
+			assert.Equal(t, tc.statusCode, rec.Code)
+
+			var resp response.Response
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, response.StatusError, resp.Status)
+			assert.Equal(t, tc.respError, resp.Error)
+		})
+	}
+}
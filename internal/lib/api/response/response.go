@@ -41,6 +41,12 @@ func ValidationError(errs validator.ValidationErrors) Response {
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is a required field", err.Field()))
 		case "url":
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is not a valid URL", err.Field()))
+		case "max":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s must be at most %s characters long", err.Field(), err.Param()))
+		case "alias":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s may only contain letters, digits, '-' and '_'", err.Field()))
+		case "reserved":
+			errMsgs = append(errMsgs, fmt.Sprintf("field %s is reserved", err.Field()))
 		default:
 			errMsgs = append(errMsgs, fmt.Sprintf("field %s is not valid", err.Field()))
 		}
@@ -0,0 +1,29 @@
+package apikey
+
+import (
+	// embedded
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// prefix makes keys easy to spot in configs and secret scanners.
+const prefix = "us_"
+
+// Generate returns a new random api key.
+func Generate() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+	return prefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash returns the hex encoded SHA-256 of key, the form keys are stored in.
+// Keys are long random strings, so a plain fast hash is enough.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
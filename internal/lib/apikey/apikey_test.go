@@ -0,0 +1,28 @@
+package apikey
+
+import (
+	// embedded
+	"strings"
+	"testing"
+
+	// external
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	key1, err := Generate()
+	require.NoError(t, err)
+	key2, err := Generate()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(key1, prefix))
+	assert.Len(t, key1, len(prefix)+43)
+	assert.NotEqual(t, key1, key2)
+}
+
+func TestHash(t *testing.T) {
+	assert.Equal(t, Hash("us_key"), Hash("us_key"))
+	assert.NotEqual(t, Hash("us_key"), Hash("us_other"))
+	assert.Len(t, Hash("us_key"), 64)
+}
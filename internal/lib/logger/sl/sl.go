@@ -2,7 +2,11 @@ package sl
 
 import (
 	// embedded
+	"context"
 	"log/slog"
+
+	// external
+	"go.opentelemetry.io/otel/trace"
 )
 
 func Err(err error) slog.Attr {
@@ -10,4 +14,14 @@ func Err(err error) slog.Attr {
 		Key:   "error",
 		Value: slog.StringValue(err.Error()),
 	}
-}
\ No newline at end of file
+}
+
+// TraceID returns the id of the trace recorded for ctx, so logs can be
+// looked up as traces. Without a trace the attribute is empty and dropped.
+func TraceID(ctx context.Context) slog.Attr {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return slog.Attr{}
+	}
+	return slog.String("trace_id", sc.TraceID().String())
+}
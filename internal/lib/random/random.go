@@ -2,21 +2,51 @@ package random
 
 import (
 	// embedded
-	"math/rand"
-	"time"
+	"crypto/rand"
 )
 
-var rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+// Alphabets to draw random strings from.
+const (
+	Base62 = "ABCDEFGHIJKLMNOPQRSTUVWXYZ" +
+		"abcdefghijklmnopqrstuvwxyz" +
+		"0123456789"
+	Lowercase = "abcdefghijklmnopqrstuvwxyz" +
+		"0123456789"
+	// Unambiguous is Base62 without 0, O, 1 and l, which are easy to mix up.
+	Unambiguous = "ABCDEFGHIJKLMNPQRSTUVWXYZ" +
+		"abcdefghijkmnopqrstuvwxyz" +
+		"23456789"
+)
 
+// NewRandomString returns a string of size characters drawn from Base62.
 func NewRandomString(size int) string {
-	chars := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ" +
-		"abcdefghijklmnopqrstuvwxyz" +
-		"0123456789")
+	return NewString(size, Base62)
+}
+
+// NewString returns a string of size characters drawn uniformly from
+// alphabet using crypto/rand. The alphabet must be ASCII and at most 256
+// characters long.
+func NewString(size int, alphabet string) string {
+	// bytes at or above limit would favour the first chars, so they are
+	// rejected and redrawn instead of taken modulo len(alphabet)
+	limit := 256 - 256%len(alphabet)
 
-	b := make([]rune, size)
-	for i := range b {
-		b[i] = chars[rnd.Intn(len(chars))]
+	b := make([]byte, size)
+	buf := make([]byte, size)
+	for i := 0; i < size; {
+		// never fails, see crypto/rand.Read
+		_, _ = rand.Read(buf)
+		for _, v := range buf {
+			if int(v) >= limit {
+				continue
+			}
+			b[i] = alphabet[int(v)%len(alphabet)]
+			i++
+			if i == size {
+				break
+			}
+		}
 	}
 
 	return string(b)
-}
\ No newline at end of file
+}
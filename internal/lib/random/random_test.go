@@ -48,4 +48,21 @@ func TestNewRandomString(t *testing.T) {
 			assert.NotEqual(t, str1, str2)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestNewString_Alphabet(t *testing.T) {
+	for _, alphabet := range []string{Base62, Lowercase, Unambiguous} {
+		counts := make(map[rune]int)
+		for range 1000 {
+			for _, c := range NewString(len(alphabet), alphabet) {
+				counts[c]++
+			}
+		}
+
+		// every char shows up and nothing outside the alphabet does
+		assert.Len(t, counts, len(alphabet))
+		for c := range counts {
+			assert.Contains(t, alphabet, string(c))
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package urlnorm
+
+import (
+	// embedded
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	// external
+	"golang.org/x/net/idna"
+)
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// Options tune Normalize. SortQuery orders query params by key, which
+// makes urls differing in param order equal but may break servers that
+// care about the order.
+type Options struct {
+	SortQuery bool
+}
+
+// Normalize returns the canonical form of an absolute url, so that
+// equivalent urls compare equal: the scheme and host are lowercased,
+// IDN hosts converted to punycode, default ports and an empty query
+// removed, dot segments resolved and an empty path replaced with "/".
+func Normalize(rawURL string, opts Options) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+	if u.Scheme == "" || u.Hostname() == "" {
+		return "", fmt.Errorf("url must be absolute: %q", rawURL)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	host, err := normalizeHost(u.Hostname())
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+	port := u.Port()
+	if port == defaultPorts[u.Scheme] {
+		port = ""
+	}
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		u.Host = "[" + host + "]"
+	default:
+		u.Host = host
+	}
+
+	escapedPath := removeDotSegments(u.EscapedPath())
+	if escapedPath == "" {
+		escapedPath = "/"
+	}
+	if u.Path, err = url.PathUnescape(escapedPath); err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+	u.RawPath = escapedPath
+
+	u.ForceQuery = false
+	if opts.SortQuery && u.RawQuery != "" {
+		// Encode sorts by key and keeps the order of values of one key
+		u.RawQuery = u.Query().Encode()
+	}
+
+	return u.String(), nil
+}
+
+func normalizeHost(host string) (string, error) {
+	host = strings.ToLower(host)
+	for i := 0; i < len(host); i++ {
+		if host[i] >= 0x80 {
+			return idna.Lookup.ToASCII(host)
+		}
+	}
+	return host, nil
+}
+
+// removeDotSegments resolves "." and ".." segments as in RFC 3986, 5.2.4.
+// Unlike path.Clean it keeps empty segments and a trailing slash.
+func removeDotSegments(p string) string {
+	if !strings.Contains(p, ".") {
+		return p
+	}
+
+	var out []string
+	segments := strings.Split(p, "/")
+	for i, seg := range segments {
+		last := i == len(segments)-1
+		switch seg {
+		case ".":
+			if last {
+				out = append(out, "")
+			}
+		case "..":
+			// the leading empty segment of an absolute path stays
+			if len(out) > 1 {
+				out = out[:len(out)-1]
+			}
+			if last {
+				out = append(out, "")
+			}
+		default:
+			out = append(out, seg)
+		}
+	}
+
+	return strings.Join(out, "/")
+}
@@ -0,0 +1,203 @@
+package cache
+
+import (
+	// project
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// URLGetter looks up the url stored under alias and when it expires, zero
+// if it never does.
+type URLGetter interface {
+	GetURLWithExpiry(ctx context.Context, alias string) (string, time.Time, error)
+}
+
+// Stats are the counters of a cache since it was created.
+type Stats struct {
+	Hits   int64
+	Misses int64
+	Size   int
+}
+
+type entry struct {
+	alias     string
+	url       string
+	err       error
+	expiresAt time.Time
+}
+
+// fetch tracks the lookups of an alias in flight. Invalidate bumps gen, a
+// lookup that started under an older gen may have read the replaced row
+// and isn't cached.
+type fetch struct {
+	pending int
+	gen     uint64
+}
+
+// Cache is a read-through LRU cache of alias lookups in front of a URLGetter.
+// Found urls are kept for ttl, but no longer than until they expire; unknown
+// and expired aliases for negativeTTL.
+// At most size entries are kept, the least recently used are evicted first.
+type Cache struct {
+	getter      URLGetter
+	size        int
+	ttl         time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	ll      *list.List // front is the most recently used
+	items   map[string]*list.Element
+	fetches map[string]*fetch
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+func New(getter URLGetter, size int, ttl time.Duration, negativeTTL time.Duration) *Cache {
+	return &Cache{
+		getter:      getter,
+		size:        size,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		now:         time.Now,
+		ll:          list.New(),
+		items:       make(map[string]*list.Element),
+		fetches:     make(map[string]*fetch),
+	}
+}
+
+// GetURL returns the url stored under alias, from the cache if possible.
+func (c *Cache) GetURL(ctx context.Context, alias string) (string, error) {
+	if url, err, ok := c.get(alias); ok {
+		c.hits.Add(1)
+		return url, err
+	}
+	c.misses.Add(1)
+
+	gen := c.startFetch(alias)
+	url, expiresAt, err := c.getter.GetURLWithExpiry(ctx, alias)
+
+	// other failures are not cached
+	var ttl time.Duration
+	switch {
+	case err == nil:
+		ttl = c.ttl
+		if !expiresAt.IsZero() {
+			ttl = min(ttl, expiresAt.Sub(c.now()))
+		}
+	case errors.Is(err, storage.ErrURLNotFound), errors.Is(err, storage.ErrURLExpired):
+		ttl = c.negativeTTL
+	}
+	c.put(alias, gen, url, err, ttl)
+
+	return url, err
+}
+
+// Invalidate drops alias from the cache.
+func (c *Cache) Invalidate(alias string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[alias]; ok {
+		c.remove(el)
+	}
+	if f, ok := c.fetches[alias]; ok {
+		f.gen++
+	}
+}
+
+// Purge drops every entry, e.g. when invalidations may have been missed.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ll.Init()
+	clear(c.items)
+	for _, f := range c.fetches {
+		f.gen++
+	}
+}
+
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	size := c.ll.Len()
+	c.mu.Unlock()
+
+	return Stats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Size:   size,
+	}
+}
+
+func (c *Cache) get(alias string) (string, error, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[alias]
+	if !ok {
+		return "", nil, false
+	}
+	e := el.Value.(*entry)
+	if !c.now().Before(e.expiresAt) {
+		c.remove(el)
+		return "", nil, false
+	}
+	c.ll.MoveToFront(el)
+	return e.url, e.err, true
+}
+
+// startFetch registers a lookup of alias and returns its generation.
+func (c *Cache) startFetch(alias string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f, ok := c.fetches[alias]
+	if !ok {
+		f = &fetch{}
+		c.fetches[alias] = f
+	}
+	f.pending++
+	return f.gen
+}
+
+// put ends the lookup of alias started under gen and caches its result
+// for ttl, unless alias was invalidated meanwhile.
+func (c *Cache) put(alias string, gen uint64, url string, err error, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f := c.fetches[alias]
+	f.pending--
+	if f.pending == 0 {
+		delete(c.fetches, alias)
+	}
+	if f.gen != gen || ttl <= 0 {
+		return
+	}
+
+	e := &entry{alias: alias, url: url, err: err, expiresAt: c.now().Add(ttl)}
+	if el, ok := c.items[alias]; ok {
+		el.Value = e
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.items[alias] = c.ll.PushFront(e)
+
+	for c.ll.Len() > c.size {
+		c.remove(c.ll.Back())
+	}
+}
+
+func (c *Cache) remove(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*entry).alias)
+}
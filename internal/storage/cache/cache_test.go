@@ -0,0 +1,193 @@
+package cache_test
+
+import (
+	// project
+	"go-url-shortener/internal/storage"
+	"go-url-shortener/internal/storage/cache"
+
+	// embedded
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	// external
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ctx = context.Background()
+
+type getterStub struct {
+	urls    map[string]string
+	expires map[string]time.Time
+	err     error
+	calls   int
+	// afterRead runs once the url is read, before it is returned
+	afterRead func()
+}
+
+func (g *getterStub) GetURLWithExpiry(ctx context.Context, alias string) (string, time.Time, error) {
+	g.calls++
+	if g.err != nil {
+		return "", time.Time{}, g.err
+	}
+	url, ok := g.urls[alias]
+	if g.afterRead != nil {
+		g.afterRead()
+	}
+	if !ok {
+		return "", time.Time{}, storage.ErrURLNotFound
+	}
+	return url, g.expires[alias], nil
+}
+
+func (g *getterStub) UpdateURL(ctx context.Context, newURL string, originalURL string, alias string, caller storage.APIKey) error {
+	g.urls[alias] = newURL
+	return nil
+}
+
+func (g *getterStub) DeleteURL(ctx context.Context, alias string, _ storage.APIKey) error {
+	delete(g.urls, alias)
+	return nil
+}
+
+func TestCache_ReadThrough(t *testing.T) {
+	getter := &getterStub{urls: map[string]string{"a": "https://a.com"}}
+	c := cache.New(getter, 10, time.Minute, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		url, err := c.GetURL(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, "https://a.com", url)
+
+		_, err = c.GetURL(ctx, "missing")
+		assert.ErrorIs(t, err, storage.ErrURLNotFound)
+	}
+
+	assert.Equal(t, 2, getter.calls)
+	assert.Equal(t, cache.Stats{Hits: 4, Misses: 2, Size: 2}, c.Stats())
+}
+
+func TestCache_Expiry(t *testing.T) {
+	getter := &getterStub{urls: map[string]string{"a": "https://a.com"}}
+	c := cache.New(getter, 10, time.Hour, 20*time.Second)
+	now := time.Now()
+	cache.SetNow(c, func() time.Time { return now })
+
+	_, _ = c.GetURL(ctx, "a")
+	_, _ = c.GetURL(ctx, "missing")
+	getter.urls["missing"] = "https://missing.com"
+
+	now = now.Add(30 * time.Second)
+
+	// the negative entry is gone, the positive one is still there
+	url, err := c.GetURL(ctx, "missing")
+	require.NoError(t, err)
+	assert.Equal(t, "https://missing.com", url)
+	_, _ = c.GetURL(ctx, "a")
+
+	assert.Equal(t, 3, getter.calls)
+}
+
+func TestCache_URLExpiry(t *testing.T) {
+	now := time.Now()
+	getter := &getterStub{
+		urls:    map[string]string{"a": "https://a.com"},
+		expires: map[string]time.Time{"a": now.Add(30 * time.Second)},
+	}
+	c := cache.New(getter, 10, time.Minute, time.Minute)
+	cache.SetNow(c, func() time.Time { return now })
+
+	_, err := c.GetURL(ctx, "a")
+	require.NoError(t, err)
+	_, err = c.GetURL(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, getter.calls)
+
+	// the link expired before the cache ttl, the storage has the say again
+	now = now.Add(31 * time.Second)
+	getter.err = storage.ErrURLExpired
+
+	_, err = c.GetURL(ctx, "a")
+	assert.ErrorIs(t, err, storage.ErrURLExpired)
+	assert.Equal(t, 2, getter.calls)
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	getter := &getterStub{urls: map[string]string{"a": "1", "b": "2", "c": "3"}}
+	c := cache.New(getter, 2, time.Minute, time.Minute)
+
+	_, _ = c.GetURL(ctx, "a")
+	_, _ = c.GetURL(ctx, "b")
+	_, _ = c.GetURL(ctx, "a") // b is now the least recently used
+	_, _ = c.GetURL(ctx, "c")
+	assert.Equal(t, 3, getter.calls)
+
+	_, _ = c.GetURL(ctx, "a")
+	assert.Equal(t, 3, getter.calls)
+	_, _ = c.GetURL(ctx, "b")
+	assert.Equal(t, 4, getter.calls)
+	assert.Equal(t, 2, c.Stats().Size)
+}
+
+func TestCache_DoesNotCacheFailures(t *testing.T) {
+	getter := &getterStub{err: errors.New("connection refused")}
+	c := cache.New(getter, 10, time.Minute, time.Minute)
+
+	_, err := c.GetURL(ctx, "a")
+	require.Error(t, err)
+	_, err = c.GetURL(ctx, "a")
+	require.Error(t, err)
+
+	assert.Equal(t, 2, getter.calls)
+	assert.Zero(t, c.Stats().Size)
+}
+
+func TestCache_Invalidation(t *testing.T) {
+	getter := &getterStub{urls: map[string]string{"a": "https://a.com"}}
+	c := cache.New(getter, 10, time.Minute, time.Minute)
+
+	_, _ = c.GetURL(ctx, "a")
+	require.NoError(t, c.Updater(getter).UpdateURL(ctx, "https://b.com", "", "a", storage.APIKey{}))
+
+	url, err := c.GetURL(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "https://b.com", url)
+
+	require.NoError(t, c.Deleter(getter).DeleteURL(ctx, "a", storage.APIKey{}))
+
+	_, err = c.GetURL(ctx, "a")
+	assert.ErrorIs(t, err, storage.ErrURLNotFound)
+	assert.Equal(t, 3, getter.calls)
+
+	_, _ = c.GetURL(ctx, "b")
+	c.Purge()
+	assert.Zero(t, c.Stats().Size)
+}
+
+func TestCache_InvalidationDuringLookup(t *testing.T) {
+	getter := &getterStub{urls: map[string]string{"a": "https://a.com"}}
+	c := cache.New(getter, 10, time.Minute, time.Minute)
+	updater := c.Updater(getter)
+
+	// the update commits and invalidates while the lookup holds the old row
+	getter.afterRead = func() {
+		getter.afterRead = nil
+		require.NoError(t, updater.UpdateURL(ctx, "https://b.com", "", "a", storage.APIKey{}))
+	}
+	url, err := c.GetURL(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "https://a.com", url)
+
+	// the stale result wasn't cached
+	url, err = c.GetURL(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "https://b.com", url)
+	assert.Equal(t, 2, getter.calls)
+
+	url, err = c.GetURL(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "https://b.com", url)
+	assert.Equal(t, 2, getter.calls)
+}
@@ -0,0 +1,11 @@
+package cache
+
+import (
+	// embedded
+	"time"
+)
+
+// SetNow replaces the clock of c, so tests can move time forward.
+func SetNow(c *Cache, now func() time.Time) {
+	c.now = now
+}
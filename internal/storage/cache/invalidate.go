@@ -0,0 +1,71 @@
+package cache
+
+import (
+	// project
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"context"
+	"time"
+)
+
+type URLSaver interface {
+	SaveURL(ctx context.Context, urlToSave string, originalURL string, alias string, expiresAt time.Time, keyID int64) error
+}
+
+type URLUpdater interface {
+	UpdateURL(ctx context.Context, newURL string, originalURL string, alias string, caller storage.APIKey) error
+}
+
+type URLDeleter interface {
+	DeleteURL(ctx context.Context, alias string, caller storage.APIKey) error
+}
+
+// Saver invalidates the alias after saving, so that a cached "not found"
+// doesn't hide a new url.
+func (c *Cache) Saver(saver URLSaver) URLSaver {
+	return &invalidatingSaver{URLSaver: saver, cache: c}
+}
+
+// Updater invalidates the alias after updating its url.
+func (c *Cache) Updater(updater URLUpdater) URLUpdater {
+	return &invalidatingUpdater{URLUpdater: updater, cache: c}
+}
+
+// Deleter invalidates the alias after deleting it.
+func (c *Cache) Deleter(deleter URLDeleter) URLDeleter {
+	return &invalidatingDeleter{URLDeleter: deleter, cache: c}
+}
+
+type invalidatingSaver struct {
+	URLSaver
+	cache *Cache
+}
+
+func (s *invalidatingSaver) SaveURL(ctx context.Context, urlToSave string, originalURL string, alias string, expiresAt time.Time, keyID int64) error {
+	err := s.URLSaver.SaveURL(ctx, urlToSave, originalURL, alias, expiresAt, keyID)
+	s.cache.Invalidate(alias)
+	return err
+}
+
+type invalidatingUpdater struct {
+	URLUpdater
+	cache *Cache
+}
+
+func (u *invalidatingUpdater) UpdateURL(ctx context.Context, newURL string, originalURL string, alias string, caller storage.APIKey) error {
+	err := u.URLUpdater.UpdateURL(ctx, newURL, originalURL, alias, caller)
+	u.cache.Invalidate(alias)
+	return err
+}
+
+type invalidatingDeleter struct {
+	URLDeleter
+	cache *Cache
+}
+
+func (d *invalidatingDeleter) DeleteURL(ctx context.Context, alias string, caller storage.APIKey) error {
+	err := d.URLDeleter.DeleteURL(ctx, alias, caller)
+	d.cache.Invalidate(alias)
+	return err
+}
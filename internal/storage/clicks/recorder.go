@@ -0,0 +1,116 @@
+package clicks
+
+import (
+	// project
+	"go-url-shortener/internal/lib/logger/sl"
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type ClickSaver interface {
+	SaveClicks(ctx context.Context, clicks []storage.Click) error
+}
+
+// Recorder buffers clicks in memory and writes them to storage in batches
+// from a background goroutine, so recording never blocks a redirect.
+// When the buffer is full new clicks are dropped.
+type Recorder struct {
+	log           *slog.Logger
+	saver         ClickSaver
+	events        chan storage.Click
+	batchSize     int
+	flushInterval time.Duration
+
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+func NewRecorder(log *slog.Logger, saver ClickSaver, bufferSize int, batchSize int, flushInterval time.Duration) *Recorder {
+	const op = "storage.clicks.NewRecorder"
+
+	r := &Recorder{
+		log:           log.With(slog.String("op", op)),
+		saver:         saver,
+		events:        make(chan storage.Click, bufferSize),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		done:          make(chan struct{}),
+	}
+	go r.run()
+
+	return r
+}
+
+// Record queues a click without blocking.
+func (r *Recorder) Record(click storage.Click) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return
+	}
+
+	select {
+	case r.events <- click:
+	default:
+		r.log.Warn("click buffer is full, click dropped",
+			slog.String("alias", click.Alias),
+			slog.String("request_id", click.RequestID),
+		)
+	}
+}
+
+// Close stops accepting clicks and blocks until the buffered ones are written.
+func (r *Recorder) Close() {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	r.closed = true
+	close(r.events)
+	r.mu.Unlock()
+
+	<-r.done
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]storage.Click, 0, r.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		// a batch outlives the requests its clicks came from
+		if err := r.saver.SaveClicks(context.Background(), batch); err != nil {
+			r.log.Error("failed to save clicks", slog.Int("count", len(batch)), sl.Err(err))
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case click, ok := <-r.events:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, click)
+			if len(batch) >= r.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
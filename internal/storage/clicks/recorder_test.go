@@ -0,0 +1,70 @@
+package clicks_test
+
+import (
+	// project
+	"go-url-shortener/internal/lib/logger/handlers/slogdiscard"
+	"go-url-shortener/internal/storage"
+	"go-url-shortener/internal/storage/clicks"
+
+	// embedded
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	// external
+	"github.com/stretchr/testify/assert"
+)
+
+type saverStub struct {
+	mu      sync.Mutex
+	batches [][]storage.Click
+}
+
+func (s *saverStub) SaveClicks(ctx context.Context, clicks []storage.Click) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.batches = append(s.batches, append([]storage.Click(nil), clicks...))
+	return nil
+}
+
+func (s *saverStub) total() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, b := range s.batches {
+		n += len(b)
+	}
+	return n
+}
+
+func TestRecorder_FlushesOnClose(t *testing.T) {
+	saver := &saverStub{}
+	r := clicks.NewRecorder(slogdiscard.NewDiscardLogger(), saver, 100, 10, time.Hour)
+
+	for i := 0; i < 25; i++ {
+		r.Record(storage.Click{Alias: "alias", At: time.Now()})
+	}
+	r.Close()
+
+	assert.Equal(t, 25, saver.total())
+	for _, b := range saver.batches {
+		assert.LessOrEqual(t, len(b), 10)
+	}
+
+	// recording after close is a no-op
+	r.Record(storage.Click{Alias: "alias"})
+	assert.Equal(t, 25, saver.total())
+}
+
+func TestRecorder_FlushesOnInterval(t *testing.T) {
+	saver := &saverStub{}
+	r := clicks.NewRecorder(slogdiscard.NewDiscardLogger(), saver, 100, 10, 10*time.Millisecond)
+	defer r.Close()
+
+	r.Record(storage.Click{Alias: "alias", At: time.Now()})
+
+	assert.Eventually(t, func() bool { return saver.total() == 1 }, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	// project
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type apiKey struct {
+	storage.APIKey
+	hash string
+}
+
+// SaveAPIKey stores a new key by its hash and returns its id.
+func (s *Storage) SaveAPIKey(ctx context.Context, name string, keyHash string, role string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastKeyID++
+	s.keys[s.lastKeyID] = &apiKey{
+		APIKey: storage.APIKey{
+			ID:        s.lastKeyID,
+			Name:      name,
+			Role:      role,
+			CreatedAt: time.Now(),
+		},
+		hash: keyHash,
+	}
+	return s.lastKeyID, nil
+}
+
+// GetAPIKey looks an active key up by its hash.
+func (s *Storage) GetAPIKey(ctx context.Context, keyHash string) (storage.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, key := range s.keys {
+		if key.hash == keyHash && key.RevokedAt.IsZero() {
+			return key.APIKey, nil
+		}
+	}
+	return storage.APIKey{}, fmt.Errorf("%w", storage.ErrAPIKeyNotFound)
+}
+
+// ListAPIKeys returns every key, revoked ones included, ordered by id.
+func (s *Storage) ListAPIKeys(ctx context.Context) ([]storage.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]storage.APIKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key.APIKey)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	return keys, nil
+}
+
+// RevokeAPIKey deactivates an active key.
+func (s *Storage) RevokeAPIKey(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[id]
+	if !ok || !key.RevokedAt.IsZero() {
+		return fmt.Errorf("%w", storage.ErrAPIKeyNotFound)
+	}
+	key.RevokedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,295 @@
+package memory
+
+import (
+	// project
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Storage keeps urls in process memory. It is meant for tests and local
+// runs where spinning up a database is not worth it.
+type Storage struct {
+	mu        sync.RWMutex
+	urls      map[string]record
+	lastID    int64
+	keys      map[int64]*apiKey
+	lastKeyID int64
+	aliasSeq  int64
+}
+
+type record struct {
+	id        int64
+	url       string
+	original  string
+	createdAt time.Time
+	keyID     int64
+	expiresAt time.Time // zero means the url never expires
+	clicks    []storage.Click
+}
+
+// originalURL is the url as submitted, the saved one if not known.
+func (r record) originalURL() string {
+	if r.original == "" {
+		return r.url
+	}
+	return r.original
+}
+
+// ownedBy reports whether caller may change the url: its owner or an admin.
+// Urls without an owner have keyID 0, which no api key has.
+func (r record) ownedBy(caller storage.APIKey) bool {
+	return caller.Role == storage.RoleAdmin || (r.keyID != 0 && r.keyID == caller.ID)
+}
+
+func (r record) expired(now time.Time) bool {
+	return !r.expiresAt.IsZero() && !r.expiresAt.After(now)
+}
+
+func NewStorage() *Storage {
+	return &Storage{
+		urls: make(map[string]record),
+		keys: make(map[int64]*apiKey),
+	}
+}
+
+// Close is a no-op, it only makes Storage interchangeable with the sql ones.
+func (s *Storage) Close() error {
+	return nil
+}
+
+// Ping always succeeds, process memory is always reachable.
+func (s *Storage) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (s *Storage) SaveURL(ctx context.Context, urlToSave string, originalURL string, alias string, expiresAt time.Time, keyID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.urls[alias]; ok {
+		return fmt.Errorf("%w", storage.ErrURlExists)
+	}
+	s.lastID++
+	s.urls[alias] = record{
+		id:        s.lastID,
+		url:       urlToSave,
+		original:  originalURL,
+		createdAt: time.Now(),
+		expiresAt: expiresAt,
+		keyID:     keyID,
+	}
+	return nil
+}
+
+func (s *Storage) GetURL(ctx context.Context, alias string) (string, error) {
+	url, _, err := s.GetURLWithExpiry(ctx, alias)
+	return url, err
+}
+
+// GetURLWithExpiry is GetURL also returning when the url expires, zero if
+// it never does.
+func (s *Storage) GetURLWithExpiry(ctx context.Context, alias string) (string, time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.urls[alias]
+	if !ok {
+		return "", time.Time{}, fmt.Errorf("%w", storage.ErrURLNotFound)
+	}
+	if rec.expired(time.Now()) {
+		return "", time.Time{}, fmt.Errorf("%w", storage.ErrURLExpired)
+	}
+	return rec.url, rec.expiresAt, nil
+}
+
+// DeleteURL removes the url stored under alias. Only the owner of the url
+// or an admin may remove it.
+func (s *Storage) DeleteURL(ctx context.Context, alias string, caller storage.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.urls[alias]
+	if !ok {
+		return fmt.Errorf("%w", storage.ErrURLNotFound)
+	}
+	if !rec.ownedBy(caller) {
+		return fmt.Errorf("%w", storage.ErrForbidden)
+	}
+	delete(s.urls, alias)
+	return nil
+}
+
+// GetURLInfo returns the url stored under alias with its metadata.
+// Expired urls are returned as well until the reaper removes them.
+func (s *Storage) GetURLInfo(ctx context.Context, alias string) (storage.URLInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.urls[alias]
+	if !ok {
+		return storage.URLInfo{}, fmt.Errorf("%w", storage.ErrURLNotFound)
+	}
+	return storage.URLInfo{
+		URL: storage.URL{
+			ID:          rec.id,
+			Alias:       alias,
+			URL:         rec.url,
+			OriginalURL: rec.originalURL(),
+			CreatedAt:   rec.createdAt,
+			ExpiresAt:   rec.expiresAt,
+			OwnerID:     rec.keyID,
+		},
+		Clicks: int64(len(rec.clicks)),
+	}, nil
+}
+
+// UpdateURL points an existing alias to newURL, keeping originalURL for
+// display. Like deletion, only the owner of the url or an admin may change it.
+func (s *Storage) UpdateURL(ctx context.Context, newURL string, originalURL string, alias string, caller storage.APIKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.urls[alias]
+	if !ok {
+		return fmt.Errorf("%w", storage.ErrURLNotFound)
+	}
+	if !rec.ownedBy(caller) {
+		return fmt.Errorf("%w", storage.ErrForbidden)
+	}
+	rec.url = newURL
+	rec.original = originalURL
+	s.urls[alias] = rec
+	return nil
+}
+
+// ListURLs returns a page of urls matching filter, ordered by id.
+func (s *Storage) ListURLs(ctx context.Context, filter storage.ListFilter) ([]storage.URL, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var urls []storage.URL
+	for alias, rec := range s.urls {
+		if rec.id <= filter.After ||
+			!strings.HasPrefix(alias, filter.AliasPrefix) ||
+			!strings.Contains(rec.url, filter.URLContains) {
+			continue
+		}
+		urls = append(urls, storage.URL{
+			ID:          rec.id,
+			Alias:       alias,
+			URL:         rec.url,
+			OriginalURL: rec.originalURL(),
+			CreatedAt:   rec.createdAt,
+			ExpiresAt:   rec.expiresAt,
+			OwnerID:     rec.keyID,
+		})
+	}
+
+	sort.Slice(urls, func(i, j int) bool { return urls[i].ID < urls[j].ID })
+	if len(urls) > filter.Limit {
+		urls = urls[:filter.Limit]
+	}
+
+	return urls, nil
+}
+
+// DeleteExpiredURLs removes every url whose expiry has passed and
+// returns how many were removed.
+func (s *Storage) DeleteExpiredURLs(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var removed int64
+	for alias, rec := range s.urls {
+		if rec.expired(now) {
+			delete(s.urls, alias)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// SaveClicks appends clicks to their urls. Clicks on aliases deleted in
+// the meantime are silently dropped.
+func (s *Storage) SaveClicks(ctx context.Context, clicks []storage.Click) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, c := range clicks {
+		rec, ok := s.urls[c.Alias]
+		if !ok {
+			continue
+		}
+		rec.clicks = append(rec.clicks, c)
+		s.urls[c.Alias] = rec
+	}
+	return nil
+}
+
+// GetStats returns the total number of clicks on alias and the daily
+// buckets of the last days, days without clicks are omitted.
+func (s *Storage) GetStats(ctx context.Context, alias string, days int) (storage.Stats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.urls[alias]
+	if !ok {
+		return storage.Stats{}, fmt.Errorf("%w", storage.ErrURLNotFound)
+	}
+
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+	byDay := make(map[string]int64)
+	for _, c := range rec.clicks {
+		if at := c.At.UTC(); !at.Before(since) {
+			byDay[at.Format(time.DateOnly)]++
+		}
+	}
+
+	stats := storage.Stats{Total: int64(len(rec.clicks))}
+	for date, clicks := range byDay {
+		stats.Daily = append(stats.Daily, storage.DailyClicks{Date: date, Clicks: clicks})
+	}
+	sort.Slice(stats.Daily, func(i, j int) bool {
+		return stats.Daily[i].Date < stats.Daily[j].Date
+	})
+
+	return stats, nil
+}
+
+// FindURL returns the alias of a never expiring url saved by the api key
+// keyID, the oldest one if there are several.
+func (s *Storage) FindURL(ctx context.Context, urlToFind string, keyID int64) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	found, foundID := "", int64(0)
+	for alias, rec := range s.urls {
+		if rec.url != urlToFind || rec.keyID != keyID || !rec.expiresAt.IsZero() {
+			continue
+		}
+		if found == "" || rec.id < foundID {
+			found, foundID = alias, rec.id
+		}
+	}
+	if found == "" {
+		return "", fmt.Errorf("%w", storage.ErrURLNotFound)
+	}
+	return found, nil
+}
+
+// NextAliasID returns the next value of the alias sequence.
+func (s *Storage) NextAliasID(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.aliasSeq++
+	return s.aliasSeq, nil
+}
@@ -0,0 +1,301 @@
+package memory_test
+
+import (
+	// project
+	"go-url-shortener/internal/storage"
+	"go-url-shortener/internal/storage/memory"
+
+	// embedded
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	// external
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	ctx   = context.Background()
+	admin = storage.APIKey{Role: storage.RoleAdmin}
+)
+
+func TestStorage_CRUD(t *testing.T) {
+	s := memory.NewStorage()
+
+	require.NoError(t, s.SaveURL(ctx, "https://google.com", "", "google", time.Time{}, 0))
+
+	err := s.SaveURL(ctx, "https://yandex.ru", "", "google", time.Time{}, 0)
+	assert.ErrorIs(t, err, storage.ErrURlExists)
+
+	url, err := s.GetURL(ctx, "google")
+	require.NoError(t, err)
+	assert.Equal(t, "https://google.com", url)
+
+	require.NoError(t, s.UpdateURL(ctx, "https://google.ru", "", "google", admin))
+
+	url, err = s.GetURL(ctx, "google")
+	require.NoError(t, err)
+	assert.Equal(t, "https://google.ru", url)
+
+	err = s.UpdateURL(ctx, "https://google.ru", "", "missing", admin)
+	assert.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	require.NoError(t, s.DeleteURL(ctx, "google", admin))
+
+	_, err = s.GetURL(ctx, "google")
+	assert.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	err = s.DeleteURL(ctx, "google", admin)
+	assert.ErrorIs(t, err, storage.ErrURLNotFound)
+}
+
+func TestStorage_Concurrent(t *testing.T) {
+	s := memory.NewStorage()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			alias := fmt.Sprintf("alias_%d", i)
+			assert.NoError(t, s.SaveURL(ctx, "https://example.com", "", alias, time.Time{}, 0))
+			_, err := s.GetURL(ctx, alias)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestStorage_Expiration(t *testing.T) {
+	s := memory.NewStorage()
+
+	require.NoError(t, s.SaveURL(ctx, "https://google.com", "", "expired", time.Now().Add(-time.Minute), 0))
+	require.NoError(t, s.SaveURL(ctx, "https://google.com", "", "alive", time.Now().Add(time.Hour), 0))
+	require.NoError(t, s.SaveURL(ctx, "https://google.com", "", "forever", time.Time{}, 0))
+
+	_, err := s.GetURL(ctx, "expired")
+	assert.ErrorIs(t, err, storage.ErrURLExpired)
+
+	removed, err := s.DeleteExpiredURLs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+
+	_, err = s.GetURL(ctx, "expired")
+	assert.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	for _, alias := range []string{"alive", "forever"} {
+		_, err = s.GetURL(ctx, alias)
+		assert.NoError(t, err)
+	}
+
+	_, expiresAt, err := s.GetURLWithExpiry(ctx, "alive")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+	_, expiresAt, err = s.GetURLWithExpiry(ctx, "forever")
+	require.NoError(t, err)
+	assert.True(t, expiresAt.IsZero())
+}
+
+func TestStorage_Stats(t *testing.T) {
+	s := memory.NewStorage()
+
+	require.NoError(t, s.SaveURL(ctx, "https://google.com", "", "google", time.Time{}, 0))
+
+	now := time.Now()
+	require.NoError(t, s.SaveClicks(ctx, []storage.Click{
+		{Alias: "google", At: now},
+		{Alias: "google", At: now},
+		{Alias: "google", At: now.AddDate(0, 0, -1)},
+		{Alias: "google", At: now.AddDate(0, 0, -40)},
+		{Alias: "missing", At: now},
+	}))
+
+	stats, err := s.GetStats(ctx, "google", 30)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), stats.Total)
+	require.Len(t, stats.Daily, 2)
+	assert.Equal(t, now.UTC().Format(time.DateOnly), stats.Daily[1].Date)
+	assert.Equal(t, int64(2), stats.Daily[1].Clicks)
+
+	_, err = s.GetStats(ctx, "missing", 30)
+	assert.ErrorIs(t, err, storage.ErrURLNotFound)
+}
+
+func TestStorage_GetURLInfo(t *testing.T) {
+	s := memory.NewStorage()
+
+	require.NoError(t, s.SaveURL(ctx, "https://google.com", "", "google", time.Time{}, 0))
+	require.NoError(t, s.SaveClicks(ctx, []storage.Click{
+		{Alias: "google", At: time.Now()},
+		{Alias: "google", At: time.Now().AddDate(0, 0, -40)},
+	}))
+
+	info, err := s.GetURLInfo(ctx, "google")
+	require.NoError(t, err)
+	assert.Equal(t, "https://google.com", info.URL.URL)
+	assert.Equal(t, int64(2), info.Clicks)
+	assert.False(t, info.CreatedAt.IsZero())
+
+	_, err = s.GetURLInfo(ctx, "missing")
+	assert.ErrorIs(t, err, storage.ErrURLNotFound)
+}
+
+func TestStorage_ListURLs(t *testing.T) {
+	s := memory.NewStorage()
+
+	require.NoError(t, s.SaveURL(ctx, "https://google.com/search", "", "go_1", time.Time{}, 0))
+	require.NoError(t, s.SaveURL(ctx, "https://yandex.ru", "", "go_2", time.Time{}, 0))
+	require.NoError(t, s.SaveURL(ctx, "https://google.com/maps", "", "maps", time.Time{}, 0))
+	require.NoError(t, s.SaveURL(ctx, "https://google.com/mail", "", "go_3", time.Time{}, 0))
+	require.NoError(t, s.SaveURL(ctx, "https://google.com/mail", "", "Go_4", time.Time{}, 0))
+
+	urls, err := s.ListURLs(ctx, storage.ListFilter{AliasPrefix: "go_", URLContains: "google", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, urls, 2)
+	assert.Equal(t, "go_1", urls[0].Alias)
+	assert.Equal(t, "go_3", urls[1].Alias)
+	assert.False(t, urls[0].CreatedAt.IsZero())
+
+	page, err := s.ListURLs(ctx, storage.ListFilter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+
+	page, err = s.ListURLs(ctx, storage.ListFilter{After: page[1].ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page, 3)
+	assert.Equal(t, "maps", page[0].Alias)
+}
+
+func TestStorage_APIKeys(t *testing.T) {
+	s := memory.NewStorage()
+
+	id, err := s.SaveAPIKey(ctx, "ci", "hash-1", storage.RoleUser)
+	require.NoError(t, err)
+
+	key, err := s.GetAPIKey(ctx, "hash-1")
+	require.NoError(t, err)
+	assert.Equal(t, id, key.ID)
+	assert.Equal(t, "ci", key.Name)
+	assert.Equal(t, storage.RoleUser, key.Role)
+
+	_, err = s.GetAPIKey(ctx, "hash-2")
+	assert.ErrorIs(t, err, storage.ErrAPIKeyNotFound)
+
+	// links remember the key that created them, only it or an admin may change them
+	require.NoError(t, s.SaveURL(ctx, "https://google.com", "", "google", time.Time{}, id))
+	require.NoError(t, s.SaveURL(ctx, "https://yandex.ru", "", "yandex", time.Time{}, 0))
+
+	info, err := s.GetURLInfo(ctx, "google")
+	require.NoError(t, err)
+	assert.Equal(t, id, info.OwnerID)
+
+	other := storage.APIKey{ID: id + 1, Role: storage.RoleUser}
+	assert.ErrorIs(t, s.DeleteURL(ctx, "google", other), storage.ErrForbidden)
+	assert.ErrorIs(t, s.DeleteURL(ctx, "yandex", key), storage.ErrForbidden)
+	assert.ErrorIs(t, s.DeleteURL(ctx, "missing", key), storage.ErrURLNotFound)
+	assert.ErrorIs(t, s.UpdateURL(ctx, "https://bing.com", "", "google", other), storage.ErrForbidden)
+	assert.ErrorIs(t, s.UpdateURL(ctx, "https://bing.com", "", "yandex", key), storage.ErrForbidden)
+	assert.ErrorIs(t, s.UpdateURL(ctx, "https://bing.com", "", "missing", key), storage.ErrURLNotFound)
+	require.NoError(t, s.UpdateURL(ctx, "https://bing.com", "", "google", key))
+	require.NoError(t, s.DeleteURL(ctx, "google", key))
+	require.NoError(t, s.DeleteURL(ctx, "yandex", admin))
+
+	require.NoError(t, s.RevokeAPIKey(ctx, id))
+	assert.ErrorIs(t, s.RevokeAPIKey(ctx, id), storage.ErrAPIKeyNotFound)
+
+	_, err = s.GetAPIKey(ctx, "hash-1")
+	assert.ErrorIs(t, err, storage.ErrAPIKeyNotFound)
+
+	keys, err := s.ListAPIKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.False(t, keys[0].RevokedAt.IsZero())
+}
+
+func TestStorage_NextAliasID(t *testing.T) {
+	s := memory.NewStorage()
+
+	for want := int64(1); want <= 3; want++ {
+		id, err := s.NextAliasID(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, want, id)
+	}
+}
+
+func TestStorage_FindURL(t *testing.T) {
+	s := memory.NewStorage()
+
+	// urls live in a map, its iteration order must not decide which alias is found
+	for _, alias := range []string{"a1", "a2", "a3", "a4", "a5"} {
+		require.NoError(t, s.SaveURL(ctx, "https://google.com", "", alias, time.Time{}, 1))
+	}
+	require.NoError(t, s.SaveURL(ctx, "https://google.com", "", "expiring", time.Now().Add(time.Hour), 2))
+
+	cases := []struct {
+		name  string
+		url   string
+		keyID int64
+		alias string
+	}{
+		{name: "Oldest", url: "https://google.com", keyID: 1, alias: "a1"},
+		{name: "Other owner", url: "https://google.com", keyID: 3},
+		{name: "Expiring", url: "https://google.com", keyID: 2},
+		{name: "Exact match", url: "https://google.com/", keyID: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			alias, err := s.FindURL(ctx, tc.url, tc.keyID)
+			if tc.alias == "" {
+				assert.ErrorIs(t, err, storage.ErrURLNotFound)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.alias, alias)
+		})
+	}
+
+	// the current target is compared, not the one saved first
+	require.NoError(t, s.UpdateURL(ctx, "https://yandex.ru", "", "a1", admin))
+
+	alias, err := s.FindURL(ctx, "https://google.com", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "a2", alias)
+
+	alias, err = s.FindURL(ctx, "https://yandex.ru", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "a1", alias)
+}
+
+func TestStorage_OriginalURL(t *testing.T) {
+	s := memory.NewStorage()
+
+	require.NoError(t, s.SaveURL(ctx, "https://example.com/b", "HTTPS://Example.com:443/a/../b", "normalized", time.Time{}, 0))
+	require.NoError(t, s.SaveURL(ctx, "https://example.com/", "", "plain", time.Time{}, 0))
+
+	info, err := s.GetURLInfo(ctx, "normalized")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/b", info.URL.URL)
+	assert.Equal(t, "HTTPS://Example.com:443/a/../b", info.OriginalURL)
+
+	urls, err := s.ListURLs(ctx, storage.ListFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, urls, 2)
+	assert.Equal(t, "HTTPS://Example.com:443/a/../b", urls[0].OriginalURL)
+	assert.Equal(t, "https://example.com/", urls[1].OriginalURL)
+
+	// an update replaces the original as well
+	require.NoError(t, s.UpdateURL(ctx, "https://example.com/c", "https://EXAMPLE.com/c", "normalized", admin))
+	info, err = s.GetURLInfo(ctx, "normalized")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/c", info.URL.URL)
+	assert.Equal(t, "https://EXAMPLE.com/c", info.OriginalURL)
+
+	require.NoError(t, s.UpdateURL(ctx, "https://example.com/d", "", "normalized", admin))
+	info, err = s.GetURLInfo(ctx, "normalized")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/d", info.OriginalURL)
+}
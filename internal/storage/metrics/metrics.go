@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	// project
+	"go-url-shortener/internal/storage"
+	"go-url-shortener/internal/storage/cache"
+
+	// embedded
+	"context"
+	"database/sql"
+	"time"
+
+	// external
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type URLSaver interface {
+	SaveURL(ctx context.Context, urlToSave string, originalURL string, alias string, expiresAt time.Time, keyID int64) error
+}
+
+type URLDeleter interface {
+	DeleteURL(ctx context.Context, alias string, caller storage.APIKey) error
+}
+
+// Links counts urls created and deleted through the api.
+type Links struct {
+	created prometheus.Counter
+	deleted prometheus.Counter
+}
+
+func NewLinks(reg prometheus.Registerer) *Links {
+	l := &Links{
+		created: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "urls_created_total",
+			Help: "Number of urls saved.",
+		}),
+		deleted: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "urls_deleted_total",
+			Help: "Number of urls deleted by their owners or admins, expired ones are not counted.",
+		}),
+	}
+	reg.MustRegister(l.created, l.deleted)
+	return l
+}
+
+// Saver counts every url saver saves successfully.
+func (l *Links) Saver(saver URLSaver) URLSaver {
+	return &countingSaver{URLSaver: saver, links: l}
+}
+
+// Deleter counts every url deleter deletes successfully.
+func (l *Links) Deleter(deleter URLDeleter) URLDeleter {
+	return &countingDeleter{URLDeleter: deleter, links: l}
+}
+
+type countingSaver struct {
+	URLSaver
+	links *Links
+}
+
+func (s *countingSaver) SaveURL(ctx context.Context, urlToSave string, originalURL string, alias string, expiresAt time.Time, keyID int64) error {
+	err := s.URLSaver.SaveURL(ctx, urlToSave, originalURL, alias, expiresAt, keyID)
+	if err == nil {
+		s.links.created.Inc()
+	}
+	return err
+}
+
+type countingDeleter struct {
+	URLDeleter
+	links *Links
+}
+
+func (d *countingDeleter) DeleteURL(ctx context.Context, alias string, caller storage.APIKey) error {
+	err := d.URLDeleter.DeleteURL(ctx, alias, caller)
+	if err == nil {
+		d.links.deleted.Inc()
+	}
+	return err
+}
+
+// CacheStatser is the redirect lookup cache.
+type CacheStatser interface {
+	Stats() cache.Stats
+}
+
+// RegisterCache exposes the hits and misses of redirect lookups in c.
+func RegisterCache(reg prometheus.Registerer, c CacheStatser) {
+	reg.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "redirect_cache_hits_total",
+			Help: "Number of redirect lookups answered by the cache.",
+		}, func() float64 { return float64(c.Stats().Hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "redirect_cache_misses_total",
+			Help: "Number of redirect lookups that went to the storage.",
+		}, func() float64 { return float64(c.Stats().Misses) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "redirect_cache_entries",
+			Help: "Number of aliases in the cache.",
+		}, func() float64 { return float64(c.Stats().Size) }),
+	)
+}
+
+// DBStatser is implemented by the sql backends.
+type DBStatser interface {
+	DBStats() sql.DBStats
+}
+
+// RegisterDB exposes the connection pool stats of db, labelled by driver.
+func RegisterDB(reg prometheus.Registerer, driver string, db DBStatser) {
+	reg.MustRegister(&dbCollector{db: db, driver: driver})
+}
+
+var (
+	dbMaxOpenDesc = prometheus.NewDesc("db_max_open_connections",
+		"Maximum number of open connections to the database.", []string{"driver"}, nil)
+	dbOpenDesc = prometheus.NewDesc("db_open_connections",
+		"Number of established connections, in use and idle.", []string{"driver"}, nil)
+	dbInUseDesc = prometheus.NewDesc("db_in_use_connections",
+		"Number of connections currently in use.", []string{"driver"}, nil)
+	dbIdleDesc = prometheus.NewDesc("db_idle_connections",
+		"Number of idle connections.", []string{"driver"}, nil)
+	dbWaitCountDesc = prometheus.NewDesc("db_wait_count_total",
+		"Number of connections waited for.", []string{"driver"}, nil)
+	dbWaitDurationDesc = prometheus.NewDesc("db_wait_duration_seconds_total",
+		"Time blocked waiting for a new connection.", []string{"driver"}, nil)
+)
+
+// dbCollector reads the pool stats once per scrape.
+type dbCollector struct {
+	db     DBStatser
+	driver string
+}
+
+func (c *dbCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dbMaxOpenDesc
+	ch <- dbOpenDesc
+	ch <- dbInUseDesc
+	ch <- dbIdleDesc
+	ch <- dbWaitCountDesc
+	ch <- dbWaitDurationDesc
+}
+
+func (c *dbCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.db.DBStats()
+	ch <- prometheus.MustNewConstMetric(dbMaxOpenDesc, prometheus.GaugeValue, float64(stats.MaxOpenConnections), c.driver)
+	ch <- prometheus.MustNewConstMetric(dbOpenDesc, prometheus.GaugeValue, float64(stats.OpenConnections), c.driver)
+	ch <- prometheus.MustNewConstMetric(dbInUseDesc, prometheus.GaugeValue, float64(stats.InUse), c.driver)
+	ch <- prometheus.MustNewConstMetric(dbIdleDesc, prometheus.GaugeValue, float64(stats.Idle), c.driver)
+	ch <- prometheus.MustNewConstMetric(dbWaitCountDesc, prometheus.CounterValue, float64(stats.WaitCount), c.driver)
+	ch <- prometheus.MustNewConstMetric(dbWaitDurationDesc, prometheus.CounterValue, stats.WaitDuration.Seconds(), c.driver)
+}
@@ -0,0 +1,71 @@
+package metrics_test
+
+import (
+	// project
+	"go-url-shortener/internal/storage"
+	"go-url-shortener/internal/storage/cache"
+	"go-url-shortener/internal/storage/memory"
+	"go-url-shortener/internal/storage/metrics"
+
+	// embedded
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	// external
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var ctx = context.Background()
+
+func TestLinks(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	links := metrics.NewLinks(reg)
+	st := memory.NewStorage()
+	saver := links.Saver(st)
+	deleter := links.Deleter(st)
+
+	require.NoError(t, saver.SaveURL(ctx, "https://a.com/", "", "a", time.Time{}, 1))
+	require.NoError(t, saver.SaveURL(ctx, "https://b.com/", "", "b", time.Time{}, 1))
+	// failures are not counted
+	require.Error(t, saver.SaveURL(ctx, "https://c.com/", "", "a", time.Time{}, 1))
+	require.NoError(t, deleter.DeleteURL(ctx, "a", storage.APIKey{ID: 1}))
+	require.Error(t, deleter.DeleteURL(ctx, "a", storage.APIKey{ID: 1}))
+
+	expected := `
+# HELP urls_created_total Number of urls saved.
+# TYPE urls_created_total counter
+urls_created_total 2
+# HELP urls_deleted_total Number of urls deleted by their owners or admins, expired ones are not counted.
+# TYPE urls_deleted_total counter
+urls_deleted_total 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected)))
+}
+
+func TestRegisterCache(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	st := memory.NewStorage()
+	require.NoError(t, st.SaveURL(ctx, "https://a.com/", "", "a", time.Time{}, 1))
+
+	c := cache.New(st, 10, time.Minute, time.Minute)
+	metrics.RegisterCache(reg, c)
+
+	_, _ = c.GetURL(ctx, "a")
+	_, _ = c.GetURL(ctx, "a")
+	_, _ = c.GetURL(ctx, "missing")
+
+	expected := `
+# HELP redirect_cache_hits_total Number of redirect lookups answered by the cache.
+# TYPE redirect_cache_hits_total counter
+redirect_cache_hits_total 1
+# HELP redirect_cache_misses_total Number of redirect lookups that went to the storage.
+# TYPE redirect_cache_misses_total counter
+redirect_cache_misses_total 2
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"redirect_cache_hits_total", "redirect_cache_misses_total"))
+}
@@ -0,0 +1,274 @@
+package migrate
+
+import (
+	// embedded
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNoMigrations  = errors.New("no migrations found")
+	ErrInvalidName   = errors.New("invalid migration file name")
+	ErrMissingDown   = errors.New("migration has no down script")
+	ErrUnknownSchema = errors.New("database schema is newer than known migrations")
+)
+
+// Migration is a single schema change. Files are named
+// <version>_<name>.up.sql and <version>_<name>.down.sql,
+// e.g. 0001_create_url.up.sql.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	lock       Lock
+}
+
+// Lock serializes the migrators of processes sharing the database, e.g.
+// replicas migrating on startup. It blocks until the lock is taken and
+// returns the func releasing it.
+type Lock func() (unlock func() error, err error)
+
+type Option func(m *Migrator)
+
+// WithLock makes every schema change hold lock.
+func WithLock(lock Lock) Option {
+	return func(m *Migrator) {
+		m.lock = lock
+	}
+}
+
+// New loads the migrations from the root of fsys and prepares the
+// schema_migrations table that tracks the applied version.
+func New(db *sql.DB, fsys fs.FS, opts ...Option) (*Migrator, error) {
+	const op = "storage.migrate.New"
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	m := &Migrator{db: db, migrations: migrations}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	err = m.locked(func() error {
+		_, err := db.Exec("CREATE TABLE IF NOT EXISTS schema_migrations(version BIGINT PRIMARY KEY)")
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return m, nil
+}
+
+// Load reads and orders the migrations found in the root of fsys.
+func Load(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, file := range files {
+		version, name, direction, err := parseName(path.Base(file))
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("%w: version %d is used by %q and %q", ErrInvalidName, version, m.Name, name)
+		}
+
+		switch direction {
+		case "up":
+			m.Up = string(body)
+		case "down":
+			m.Down = string(body)
+		}
+	}
+
+	if len(byVersion) == 0 {
+		return nil, ErrNoMigrations
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("%w: %04d_%s has no up script", ErrInvalidName, m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Version returns the latest applied migration version, 0 if none.
+func (m *Migrator) Version() (int64, error) {
+	var version sql.NullInt64
+	err := m.db.QueryRow("SELECT MAX(version) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return version.Int64, nil
+}
+
+// Up applies every pending migration in order and returns the applied ones.
+func (m *Migrator) Up() (applied []Migration, err error) {
+	err = m.locked(func() error {
+		applied, err = m.up()
+		return err
+	})
+	return applied, err
+}
+
+func (m *Migrator) up() ([]Migration, error) {
+	const op = "storage.migrate.Up"
+
+	current, err := m.Version()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if last := m.migrations[len(m.migrations)-1].Version; current > last {
+		return nil, fmt.Errorf("%s: %w: %d > %d", op, ErrUnknownSchema, current, last)
+	}
+
+	var applied []Migration
+	for _, mg := range m.migrations {
+		if mg.Version <= current {
+			continue
+		}
+		// version is an int64, so formatting it in keeps the statement
+		// free of driver specific placeholders
+		insert := fmt.Sprintf("INSERT INTO schema_migrations(version) VALUES (%d)", mg.Version)
+		if err := m.apply(mg.Up, insert); err != nil {
+			return applied, fmt.Errorf("%s: %04d_%s: %w", op, mg.Version, mg.Name, err)
+		}
+		applied = append(applied, mg)
+	}
+
+	return applied, nil
+}
+
+// Down rolls back the last steps applied migrations and returns them.
+func (m *Migrator) Down(steps int) (reverted []Migration, err error) {
+	err = m.locked(func() error {
+		reverted, err = m.down(steps)
+		return err
+	})
+	return reverted, err
+}
+
+func (m *Migrator) down(steps int) ([]Migration, error) {
+	const op = "storage.migrate.Down"
+
+	current, err := m.Version()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var reverted []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+		mg := m.migrations[i]
+		if mg.Version > current {
+			continue
+		}
+		if mg.Down == "" {
+			return reverted, fmt.Errorf("%s: %04d_%s: %w", op, mg.Version, mg.Name, ErrMissingDown)
+		}
+		remove := fmt.Sprintf("DELETE FROM schema_migrations WHERE version = %d", mg.Version)
+		if err := m.apply(mg.Down, remove); err != nil {
+			return reverted, fmt.Errorf("%s: %04d_%s: %w", op, mg.Version, mg.Name, err)
+		}
+		reverted = append(reverted, mg)
+	}
+
+	return reverted, nil
+}
+
+// locked runs fn holding the lock, if there is one.
+func (m *Migrator) locked(fn func() error) error {
+	if m.lock == nil {
+		return fn()
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	err = fn()
+	if unlockErr := unlock(); unlockErr != nil && err == nil {
+		err = fmt.Errorf("failed to release migration lock: %w", unlockErr)
+	}
+	return err
+}
+
+func (m *Migrator) apply(script string, bookkeeping string) error {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.Exec(script); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(bookkeeping); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func parseName(file string) (version int64, name string, direction string, err error) {
+	base, ok := strings.CutSuffix(file, ".sql")
+	if !ok {
+		return 0, "", "", fmt.Errorf("%w: %s", ErrInvalidName, file)
+	}
+
+	dot := strings.LastIndexByte(base, '.')
+	if dot < 0 {
+		return 0, "", "", fmt.Errorf("%w: %s", ErrInvalidName, file)
+	}
+	base, direction = base[:dot], base[dot+1:]
+	if direction != "up" && direction != "down" {
+		return 0, "", "", fmt.Errorf("%w: %s", ErrInvalidName, file)
+	}
+
+	rawVersion, name, ok := strings.Cut(base, "_")
+	if !ok || name == "" {
+		return 0, "", "", fmt.Errorf("%w: %s", ErrInvalidName, file)
+	}
+	version, err = strconv.ParseInt(rawVersion, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, "", "", fmt.Errorf("%w: %s", ErrInvalidName, file)
+	}
+
+	return version, name, direction, nil
+}
@@ -0,0 +1,157 @@
+package migrate_test
+
+import (
+	// project
+	"go-url-shortener/internal/storage/migrate"
+
+	// embedded
+	"database/sql"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	// external
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"0002_add_hits.up.sql":     {Data: []byte("ALTER TABLE url ADD COLUMN hits INTEGER NOT NULL DEFAULT 0;")},
+		"0002_add_hits.down.sql":   {Data: []byte("ALTER TABLE url DROP COLUMN hits;")},
+		"0001_create_url.up.sql":   {Data: []byte("CREATE TABLE url(id INTEGER PRIMARY KEY, alias TEXT UNIQUE NOT NULL);")},
+		"0001_create_url.down.sql": {Data: []byte("DROP TABLE url;")},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	migrations, err := migrate.Load(testFS())
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "create_url", migrations[0].Name)
+	assert.Equal(t, int64(2), migrations[1].Version)
+	assert.Equal(t, "add_hits", migrations[1].Name)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	cases := []struct {
+		name string
+		fsys fstest.MapFS
+		err  error
+	}{
+		{
+			name: "Empty",
+			fsys: fstest.MapFS{},
+			err:  migrate.ErrNoMigrations,
+		},
+		{
+			name: "No version",
+			fsys: fstest.MapFS{"create_url.up.sql": {Data: []byte("SELECT 1;")}},
+			err:  migrate.ErrInvalidName,
+		},
+		{
+			name: "No direction",
+			fsys: fstest.MapFS{"0001_create_url.sql": {Data: []byte("SELECT 1;")}},
+			err:  migrate.ErrInvalidName,
+		},
+		{
+			name: "Down only",
+			fsys: fstest.MapFS{"0001_create_url.down.sql": {Data: []byte("SELECT 1;")}},
+			err:  migrate.ErrInvalidName,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := migrate.Load(tc.fsys)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestMigrator_UpDown(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+
+	m, err := migrate.New(db, testFS())
+	require.NoError(t, err)
+
+	applied, err := m.Up()
+	require.NoError(t, err)
+	assert.Len(t, applied, 2)
+
+	version, err := m.Version()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+
+	_, err = db.Exec("INSERT INTO url(alias, hits) VALUES ('a', 1)")
+	require.NoError(t, err)
+
+	// second run is a no-op
+	applied, err = m.Up()
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	reverted, err := m.Down(1)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, int64(2), reverted[0].Version)
+
+	version, err = m.Version()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+
+	_, err = db.Exec("INSERT INTO url(alias, hits) VALUES ('b', 1)")
+	assert.Error(t, err)
+
+	reverted, err = m.Down(5)
+	require.NoError(t, err)
+	assert.Len(t, reverted, 1)
+
+	version, err = m.Version()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), version)
+}
+
+func TestMigrator_Lock(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+
+	var locked bool
+	var calls []string
+	lock := func() (func() error, error) {
+		require.False(t, locked, "lock taken twice")
+		locked = true
+		calls = append(calls, "lock")
+		return func() error {
+			locked = false
+			calls = append(calls, "unlock")
+			return nil
+		}, nil
+	}
+
+	m, err := migrate.New(db, testFS(), migrate.WithLock(lock))
+	require.NoError(t, err)
+
+	_, err = m.Up()
+	require.NoError(t, err)
+	_, err = m.Down(1)
+	require.NoError(t, err)
+
+	assert.False(t, locked)
+	assert.Equal(t, []string{"lock", "unlock", "lock", "unlock", "lock", "unlock"}, calls)
+
+	failing := func() (func() error, error) { return nil, errors.New("lock timeout") }
+	m, err = migrate.New(db, testFS())
+	require.NoError(t, err)
+	migrate.WithLock(failing)(m)
+	_, err = m.Up()
+	assert.ErrorContains(t, err, "lock timeout")
+}
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	// project
+	"go-url-shortener/internal/storage"
+
+	// embedded
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// SaveAPIKey stores a new key by its hash and returns its id.
+func (s *Storage) SaveAPIKey(ctx context.Context, name string, keyHash string, role string) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx,
+		"INSERT INTO api_keys(name, key_hash, role) VALUES ($1, $2, $3) RETURNING id",
+		name, keyHash, role,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+	return id, nil
+}
+
+// GetAPIKey looks an active key up by its hash.
+func (s *Storage) GetAPIKey(ctx context.Context, keyHash string) (storage.APIKey, error) {
+	var key storage.APIKey
+	err := s.db.QueryRowContext(ctx,
+		"SELECT id, name, role, created_at FROM api_keys WHERE key_hash=$1 AND revoked_at IS NULL",
+		keyHash,
+	).Scan(&key.ID, &key.Name, &key.Role, &key.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return storage.APIKey{}, fmt.Errorf("%w", storage.ErrAPIKeyNotFound)
+		}
+		return storage.APIKey{}, fmt.Errorf("%w", err)
+	}
+	return key, nil
+}
+
+// ListAPIKeys returns every key, revoked ones included, ordered by id.
+func (s *Storage) ListAPIKeys(ctx context.Context) ([]storage.APIKey, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, name, role, created_at, revoked_at FROM api_keys ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+
+	var keys []storage.APIKey
+	for rows.Next() {
+		var key storage.APIKey
+		var revokedAt sql.NullTime
+		if err := rows.Scan(&key.ID, &key.Name, &key.Role, &key.CreatedAt, &revokedAt); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		key.RevokedAt = revokedAt.Time
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey deactivates an active key.
+func (s *Storage) RevokeAPIKey(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx, "UPDATE api_keys SET revoked_at=now() WHERE id=$1 AND revoked_at IS NULL", id)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	if ra == 0 {
+		return fmt.Errorf("%w", storage.ErrAPIKeyNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	// project
+	"go-url-shortener/internal/lib/logger/sl"
+
+	// embedded
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	// external
+	"github.com/lib/pq"
+)
+
+// InvalidationChannel carries the aliases of deleted and updated urls.
+// Every statement that does so sends pg_notify in the same query, so the
+// notification is delivered only once the change is committed.
+const InvalidationChannel = "url_invalidation"
+
+const (
+	listenerMinReconnect = 10 * time.Second
+	listenerMaxReconnect = time.Minute
+	listenerPingInterval = 90 * time.Second
+)
+
+// Invalidator is a local cache of alias lookups.
+type Invalidator interface {
+	Invalidate(alias string)
+	Purge()
+}
+
+// ListenInvalidations evicts the aliases notified on InvalidationChannel
+// from invalidator until ctx is done. A lost connection is re-established
+// in the background; as notifications sent meanwhile are lost, the whole
+// cache is purged once it is back.
+func (s *Storage) ListenInvalidations(ctx context.Context, log *slog.Logger, invalidator Invalidator) error {
+	const op = "storage.postgres.ListenInvalidations"
+
+	log = log.With(slog.String("op", op))
+
+	listener := pq.NewListener(s.dsn, listenerMinReconnect, listenerMaxReconnect, func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventDisconnected:
+			log.Warn("invalidation listener disconnected", sl.Err(err))
+		case pq.ListenerEventConnectionAttemptFailed:
+			log.Warn("invalidation listener failed to reconnect", sl.Err(err))
+		case pq.ListenerEventReconnected:
+			log.Info("invalidation listener reconnected")
+		}
+	})
+	defer listener.Close()
+
+	// Listen waits for the first connection without watching ctx, closing
+	// the listener releases it when shutdown comes first
+	stop := context.AfterFunc(ctx, func() { _ = listener.Close() })
+	defer stop()
+
+	if err := listener.Listen(InvalidationChannel); err != nil {
+		if ctx.Err() != nil {
+			log.Info("invalidation listener stopped before it was connected")
+			return nil
+		}
+		return fmt.Errorf("%w", err)
+	}
+	log.Info("invalidation listener started", slog.String("channel", InvalidationChannel))
+
+	// a ping now and then notices a dead connection that would otherwise
+	// only be detected by the tcp keepalive
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("invalidation listener stopped")
+			return nil
+		case n, ok := <-listener.Notify:
+			if !ok {
+				// closed along with the listener on shutdown
+				log.Info("invalidation listener stopped")
+				return nil
+			}
+			if n == nil {
+				invalidator.Purge()
+				continue
+			}
+			invalidator.Invalidate(n.Extra)
+		case <-ticker.C:
+			go func() { _ = listener.Ping() }()
+		}
+	}
+}
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS url(
+    id BIGSERIAL PRIMARY KEY,
+    alias TEXT UNIQUE NOT NULL,
+    url TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_alias ON url(alias);
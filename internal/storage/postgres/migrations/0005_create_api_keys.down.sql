@@ -0,0 +1,2 @@
+ALTER TABLE url DROP COLUMN key_id;
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS api_keys(
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    key_hash TEXT UNIQUE NOT NULL,
+    role TEXT NOT NULL DEFAULT 'user',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revoked_at TIMESTAMPTZ
+);
+ALTER TABLE url ADD COLUMN key_id BIGINT REFERENCES api_keys(id) ON DELETE SET NULL;
@@ -0,0 +1,3 @@
+-- source of counter based aliases, separate from url.id so that the
+-- alias is known before the row is inserted
+CREATE SEQUENCE IF NOT EXISTS alias_seq;
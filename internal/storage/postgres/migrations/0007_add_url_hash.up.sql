@@ -0,0 +1,3 @@
+ALTER TABLE url ADD COLUMN url_hash TEXT;
+UPDATE url SET url_hash = encode(sha256(convert_to(url, 'UTF8')), 'hex');
+CREATE INDEX IF NOT EXISTS idx_url_hash ON url(url_hash);
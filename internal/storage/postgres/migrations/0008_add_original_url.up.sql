@@ -0,0 +1,2 @@
+-- the url as submitted, NULL when it is already normalized
+ALTER TABLE url ADD COLUMN original_url TEXT;
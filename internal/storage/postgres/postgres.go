@@ -4,22 +4,43 @@ import (
 	// project
 	"go-url-shortener/internal/config"
 	"go-url-shortener/internal/storage"
+	"go-url-shortener/internal/storage/migrate"
 
 	// embedded
+	"context"
 	"database/sql"
+	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
 
 	// external 
+	"github.com/XSAM/otelsql"
 	"github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 )
 
+//go:embed migrations/*.sql
+var migrations embed.FS
+
 type Storage struct {
-	db *sql.DB
+	db  *sql.DB
+	dsn string
 }
 
 func NewStorage(dbInfo config.PostgresConfig) (*Storage, error) {
 	psqlInfo := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", dbInfo.Host, dbInfo.Port, dbInfo.User, dbInfo.Password, dbInfo.DBName)
-	db, err := sql.Open("postgres", psqlInfo)
+	// every query gets a child span of the request it runs for
+	db, err := otelsql.Open("postgres", psqlInfo,
+		otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			DisableErrSkip:       true,
+			OmitConnResetSession: true,
+		}),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
@@ -27,18 +48,61 @@ func NewStorage(dbInfo config.PostgresConfig) (*Storage, error) {
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
-	stmt := "CREATE TABLE IF NOT EXISTS url(id BIGSERIAL PRIMARY KEY, alias TEXT UNIQUE NOT NULL, url TEXT NOT NULL); CREATE INDEX IF NOT EXISTS idx_alias ON url(alias);"
+	return &Storage{db: db, dsn: psqlInfo}, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// Ping checks that the database is reachable.
+func (s *Storage) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+// DBStats returns the connection pool stats.
+func (s *Storage) DBStats() sql.DBStats {
+	return s.db.Stats()
+}
+
+// Migrator returns a migrator over the schema migrations embedded in the binary.
+func (s *Storage) Migrator() (*migrate.Migrator, error) {
+	fsys, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return migrate.New(s.db, fsys, migrate.WithLock(s.lockMigrations))
+}
 
-	_, err = db.Exec(stmt)
+// migrationLockKey keys the advisory lock held while migrating.
+const migrationLockKey int64 = 0x75726c5f6d6967 // "url_mig"
+
+// lockMigrations takes a session level advisory lock, so replicas starting
+// together migrate one after another. The session is kept on a dedicated
+// connection until the lock is released.
+func (s *Storage) lockMigrations() (func() error, error) {
+	ctx := context.Background()
+
+	conn, err := s.db.Conn(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("%w", err)
+	}
 
-	return &Storage{db: db}, nil
+	return func() error {
+		_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockKey)
+		return errors.Join(err, conn.Close())
+	}, nil
 }
 
-func (s *Storage) SaveURL(urlToSave string, alias string) error {
-	_, err := s.db.Exec("INSERT INTO url(url, alias) VALUES ($1, $2)", urlToSave, alias)
+func (s *Storage) SaveURL(ctx context.Context, urlToSave string, originalURL string, alias string, expiresAt time.Time, keyID int64) error {
+	_, err := s.db.ExecContext(ctx, "INSERT INTO url(url, original_url, alias, expires_at, key_id, url_hash) VALUES ($1, $2, $3, $4, $5, $6)", urlToSave, nullOriginal(originalURL, urlToSave), alias, nullTime(expiresAt), nullID(keyID), storage.URLHash(urlToSave))
 	if err != nil {
 		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
 			return fmt.Errorf("%w", storage.ErrURlExists)
@@ -48,29 +112,271 @@ func (s *Storage) SaveURL(urlToSave string, alias string) error {
 	return nil
 }
 
-func (s *Storage) GetURL(alias string) (string, error) {
+func (s *Storage) GetURL(ctx context.Context, alias string) (string, error) {
+	url, _, err := s.GetURLWithExpiry(ctx, alias)
+	return url, err
+}
+
+// GetURLWithExpiry is GetURL also returning when the url expires, zero if
+// it never does.
+func (s *Storage) GetURLWithExpiry(ctx context.Context, alias string) (string, time.Time, error) {
 	var url string
-	err := s.db.QueryRow("SELECT url FROM url WHERE alias=$1", alias).Scan(&url)
+	var expiresAt sql.NullTime
+	err := s.db.QueryRowContext(ctx, "SELECT url, expires_at FROM url WHERE alias=$1", alias).Scan(&url, &expiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", time.Time{}, fmt.Errorf("%w", storage.ErrURLNotFound)
+		}
+		return "", time.Time{}, fmt.Errorf("%w", err)
+	}
+	if expiresAt.Valid && !expiresAt.Time.After(time.Now()) {
+		return "", time.Time{}, fmt.Errorf("%w", storage.ErrURLExpired)
+	}
+	return url, expiresAt.Time, nil
+}
+
+// DeleteURL removes the url stored under alias. Only the owner of the url
+// or an admin may remove it. Other instances are notified to drop it from
+// their caches.
+func (s *Storage) DeleteURL(ctx context.Context, alias string, caller storage.APIKey) error {
+	res, err := s.db.ExecContext(ctx,
+		"WITH deleted AS (DELETE FROM url WHERE alias=$1 AND ($2 OR key_id=$3) RETURNING alias) SELECT pg_notify($4, alias) FROM deleted",
+		alias, caller.Role == storage.RoleAdmin, caller.ID, InvalidationChannel,
+	)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	if ra == 0 {
+		return s.notModified(ctx, alias)
+	}
+	return nil
+}
+
+// notModified explains why a statement owner-restricted to caller touched no
+// url: either there is no such url or it belongs to someone else.
+func (s *Storage) notModified(ctx context.Context, alias string) error {
+	var exists bool
+	err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM url WHERE alias=$1)", alias).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	if exists {
+		return fmt.Errorf("%w", storage.ErrForbidden)
+	}
+	return fmt.Errorf("%w", storage.ErrURLNotFound)
+}
+
+// GetURLInfo returns the url stored under alias with its metadata.
+// Expired urls are returned as well until the reaper removes them.
+func (s *Storage) GetURLInfo(ctx context.Context, alias string) (storage.URLInfo, error) {
+	var info storage.URLInfo
+	var expiresAt sql.NullTime
+	var ownerID sql.NullInt64
+	err := s.db.QueryRowContext(ctx,
+		"SELECT u.id, u.alias, u.url, COALESCE(u.original_url, u.url), u.created_at, u.expires_at, u.key_id, (SELECT COUNT(*) FROM clicks c WHERE c.url_id = u.id) FROM url u WHERE u.alias=$1",
+		alias,
+	).Scan(&info.ID, &info.Alias, &info.URL.URL, &info.OriginalURL, &info.CreatedAt, &expiresAt, &ownerID, &info.Clicks)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return storage.URLInfo{}, fmt.Errorf("%w", storage.ErrURLNotFound)
+		}
+		return storage.URLInfo{}, fmt.Errorf("%w", err)
+	}
+	info.ExpiresAt = expiresAt.Time
+	info.OwnerID = ownerID.Int64
+	return info, nil
+}
+
+// UpdateURL points an existing alias to newURL, keeping originalURL for
+// display, and notifies other instances.
+// Like deletion, only the owner of the url or an admin may change it.
+func (s *Storage) UpdateURL(ctx context.Context, newURL string, originalURL string, alias string, caller storage.APIKey) error {
+	res, err := s.db.ExecContext(ctx,
+		"WITH updated AS (UPDATE url SET url=$1, url_hash=$2, original_url=$3 WHERE alias=$4 AND ($5 OR key_id=$6) RETURNING alias) SELECT pg_notify($7, alias) FROM updated",
+		newURL, storage.URLHash(newURL), nullOriginal(originalURL, newURL), alias, caller.Role == storage.RoleAdmin, caller.ID, InvalidationChannel,
+	)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	if ra == 0 {
+		return s.notModified(ctx, alias)
+	}
+	return nil
+}
+
+// ListURLs returns a page of urls matching filter, ordered by id.
+func (s *Storage) ListURLs(ctx context.Context, filter storage.ListFilter) ([]storage.URL, error) {
+	conds := []string{"id > $1"}
+	args := []any{filter.After}
+	if filter.AliasPrefix != "" {
+		args = append(args, filter.AliasPrefix)
+		conds = append(conds, "starts_with(alias, $"+strconv.Itoa(len(args))+")")
+	}
+	if filter.URLContains != "" {
+		args = append(args, filter.URLContains)
+		conds = append(conds, "strpos(url, $"+strconv.Itoa(len(args))+") > 0")
+	}
+	args = append(args, filter.Limit)
+
+	query := "SELECT id, alias, url, COALESCE(original_url, url), created_at, expires_at, key_id FROM url WHERE " +
+		strings.Join(conds, " AND ") + " ORDER BY id LIMIT $" + strconv.Itoa(len(args))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+
+	var urls []storage.URL
+	for rows.Next() {
+		var u storage.URL
+		var expiresAt sql.NullTime
+		var ownerID sql.NullInt64
+		if err := rows.Scan(&u.ID, &u.Alias, &u.URL, &u.OriginalURL, &u.CreatedAt, &expiresAt, &ownerID); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		u.ExpiresAt = expiresAt.Time
+		u.OwnerID = ownerID.Int64
+		urls = append(urls, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return urls, nil
+}
+
+// DeleteExpiredURLs removes every url whose expiry has passed and
+// returns how many rows were removed.
+func (s *Storage) DeleteExpiredURLs(ctx context.Context) (int64, error) {
+	res, err := s.db.ExecContext(ctx,
+		"WITH deleted AS (DELETE FROM url WHERE expires_at IS NOT NULL AND expires_at <= $1 RETURNING alias) SELECT pg_notify($2, alias) FROM deleted",
+		time.Now().UTC(), InvalidationChannel,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+	return ra, nil
+}
+
+// FindURL returns the alias of a never expiring url saved by the api key
+// keyID, the oldest one if there are several.
+func (s *Storage) FindURL(ctx context.Context, urlToFind string, keyID int64) (string, error) {
+	var alias string
+	err := s.db.QueryRowContext(ctx,
+		"SELECT alias FROM url WHERE url_hash=$1 AND url=$2 AND key_id IS NOT DISTINCT FROM $3 AND expires_at IS NULL ORDER BY id LIMIT 1",
+		storage.URLHash(urlToFind), urlToFind, nullID(keyID),
+	).Scan(&alias)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return "", fmt.Errorf("%w", storage.ErrURLNotFound)
 		}
 		return "", fmt.Errorf("%w", err)
 	}
-	return url, nil
-}
-
-func (s *Storage) DeleteURL(alias string) error {
-    res, err := s.db.Exec("DELETE FROM url WHERE alias=$1", alias)
-    if err != nil {
-        return fmt.Errorf("%w", err)
-    }
-    ra, err := res.RowsAffected()
-    if err != nil {
-        return fmt.Errorf("%w", err)
-    }
-    if ra == 0 {
-        return fmt.Errorf("%w", storage.ErrURLNotFound)
-    }
-    return nil
+	return alias, nil
+}
+
+// NextAliasID returns the next value of the alias sequence.
+func (s *Storage) NextAliasID(ctx context.Context) (int64, error) {
+	var id int64
+	if err := s.db.QueryRowContext(ctx, "SELECT nextval('alias_seq')").Scan(&id); err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+	return id, nil
+}
+
+// nullTime maps the zero time, meaning "never expires", to NULL.
+func nullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+// nullOriginal stores the original url only when it differs from the saved one.
+func nullOriginal(originalURL string, urlToSave string) sql.NullString {
+	return sql.NullString{String: originalURL, Valid: originalURL != "" && originalURL != urlToSave}
+}
+
+// nullID maps the zero id, meaning "unknown", to NULL.
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+// SaveClicks stores a batch of clicks in one transaction. Clicks on
+// aliases deleted in the meantime are silently dropped.
+func (s *Storage) SaveClicks(ctx context.Context, clicks []storage.Click) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO clicks(url_id, clicked_at, referrer, user_agent, request_id) SELECT id, $2, $3, $4, $5 FROM url WHERE alias=$1")
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	defer stmt.Close()
+
+	for _, c := range clicks {
+		if _, err := stmt.ExecContext(ctx, c.Alias, c.At.UTC(), c.Referrer, c.UserAgent, c.RequestID); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+// GetStats returns the total number of clicks on alias and the daily
+// buckets of the last days, days without clicks are omitted.
+func (s *Storage) GetStats(ctx context.Context, alias string, days int) (storage.Stats, error) {
+	var urlID int64
+	err := s.db.QueryRowContext(ctx, "SELECT id FROM url WHERE alias=$1", alias).Scan(&urlID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return storage.Stats{}, fmt.Errorf("%w", storage.ErrURLNotFound)
+		}
+		return storage.Stats{}, fmt.Errorf("%w", err)
+	}
+
+	var stats storage.Stats
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM clicks WHERE url_id=$1", urlID).Scan(&stats.Total)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("%w", err)
+	}
+
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+	rows, err := s.db.QueryContext(ctx, "SELECT to_char(clicked_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day, COUNT(*) FROM clicks WHERE url_id=$1 AND clicked_at >= $2 GROUP BY day ORDER BY day", urlID, since)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day storage.DailyClicks
+		if err := rows.Scan(&day.Date, &day.Clicks); err != nil {
+			return storage.Stats{}, fmt.Errorf("%w", err)
+		}
+		stats.Daily = append(stats.Daily, day)
+	}
+	if err := rows.Err(); err != nil {
+		return storage.Stats{}, fmt.Errorf("%w", err)
+	}
+
+	return stats, nil
 }
@@ -0,0 +1,43 @@
+package reaper
+
+import (
+	// project
+	"go-url-shortener/internal/lib/logger/sl"
+
+	// embedded
+	"context"
+	"log/slog"
+	"time"
+)
+
+type ExpiredURLDeleter interface {
+	DeleteExpiredURLs(ctx context.Context) (int64, error)
+}
+
+// Run purges expired urls every interval until ctx is done.
+func Run(ctx context.Context, log *slog.Logger, deleter ExpiredURLDeleter, interval time.Duration) {
+	const op = "storage.reaper.Run"
+
+	log = log.With(slog.String("op", op))
+	log.Info("reaper started", slog.String("interval", interval.String()))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("reaper stopped")
+			return
+		case <-ticker.C:
+			removed, err := deleter.DeleteExpiredURLs(ctx)
+			if err != nil {
+				log.Error("failed to delete expired urls", sl.Err(err))
+				continue
+			}
+			if removed > 0 {
+				log.Info("expired urls deleted", slog.Int64("removed", removed))
+			}
+		}
+	}
+}
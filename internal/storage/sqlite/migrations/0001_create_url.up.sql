@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS url(
+    id INTEGER PRIMARY KEY,
+    alias TEXT UNIQUE NOT NULL,
+    url TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_alias ON url(alias);
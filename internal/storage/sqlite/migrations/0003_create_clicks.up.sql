@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS clicks(
+    id INTEGER PRIMARY KEY,
+    url_id INTEGER NOT NULL REFERENCES url(id) ON DELETE CASCADE,
+    clicked_at TIMESTAMP NOT NULL,
+    referrer TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_clicks_url_id_clicked_at ON clicks(url_id, clicked_at);
@@ -0,0 +1,4 @@
+-- sqlite can't add a column with a non-constant default,
+-- so existing rows are backfilled and new ones set it on insert
+ALTER TABLE url ADD COLUMN created_at TIMESTAMP;
+UPDATE url SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
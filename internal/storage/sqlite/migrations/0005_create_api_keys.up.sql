@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS api_keys(
+    id INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    key_hash TEXT UNIQUE NOT NULL,
+    role TEXT NOT NULL DEFAULT 'user',
+    created_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+-- no REFERENCES here: sqlite can't drop a column that is part of a
+-- foreign key, and keys are only ever revoked, never deleted
+ALTER TABLE url ADD COLUMN key_id INTEGER;
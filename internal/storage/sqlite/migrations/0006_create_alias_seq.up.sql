@@ -0,0 +1,5 @@
+-- sqlite has no sequences; AUTOINCREMENT never reuses ids, even of
+-- deleted rows, so old rows can be removed
+CREATE TABLE IF NOT EXISTS alias_seq(
+    id INTEGER PRIMARY KEY AUTOINCREMENT
+);
@@ -0,0 +1,4 @@
+-- sqlite has no sha256, urls saved before this migration keep a NULL
+-- hash and are not deduplicated
+ALTER TABLE url ADD COLUMN url_hash TEXT;
+CREATE INDEX IF NOT EXISTS idx_url_hash ON url(url_hash);
@@ -0,0 +1,366 @@
+package sqlite
+
+import (
+	// project
+	"go-url-shortener/internal/storage"
+	"go-url-shortener/internal/storage/migrate"
+
+	// embedded
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	// external
+	"github.com/mattn/go-sqlite3"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+type Storage struct {
+	db *sql.DB
+}
+
+func NewStorage(storagePath string) (*Storage, error) {
+	if err := os.MkdirAll(filepath.Dir(storagePath), 0o755); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	// foreign keys are off by default in sqlite, clicks rely on them
+	db, err := sql.Open("sqlite3", storagePath+"?_foreign_keys=on&_busy_timeout=5000")
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	err = db.Ping()
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return &Storage{db: db}, nil
+}
+
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+// Ping checks that the database is reachable.
+func (s *Storage) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+// DBStats returns the connection pool stats.
+func (s *Storage) DBStats() sql.DBStats {
+	return s.db.Stats()
+}
+
+// Migrator returns a migrator over the schema migrations embedded in the binary.
+func (s *Storage) Migrator() (*migrate.Migrator, error) {
+	fsys, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return migrate.New(s.db, fsys)
+}
+
+func (s *Storage) SaveURL(ctx context.Context, urlToSave string, originalURL string, alias string, expiresAt time.Time, keyID int64) error {
+	_, err := s.db.ExecContext(ctx, "INSERT INTO url(url, original_url, alias, expires_at, created_at, key_id, url_hash) VALUES (?, ?, ?, ?, ?, ?, ?)", urlToSave, nullOriginal(originalURL, urlToSave), alias, nullTime(expiresAt), time.Now().UTC(), nullID(keyID), storage.URLHash(urlToSave))
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return fmt.Errorf("%w", storage.ErrURlExists)
+		}
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+func (s *Storage) GetURL(ctx context.Context, alias string) (string, error) {
+	url, _, err := s.GetURLWithExpiry(ctx, alias)
+	return url, err
+}
+
+// GetURLWithExpiry is GetURL also returning when the url expires, zero if
+// it never does.
+func (s *Storage) GetURLWithExpiry(ctx context.Context, alias string) (string, time.Time, error) {
+	var url string
+	var expiresAt sql.NullTime
+	err := s.db.QueryRowContext(ctx, "SELECT url, expires_at FROM url WHERE alias=?", alias).Scan(&url, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", time.Time{}, fmt.Errorf("%w", storage.ErrURLNotFound)
+		}
+		return "", time.Time{}, fmt.Errorf("%w", err)
+	}
+	if expiresAt.Valid && !expiresAt.Time.After(time.Now()) {
+		return "", time.Time{}, fmt.Errorf("%w", storage.ErrURLExpired)
+	}
+	return url, expiresAt.Time, nil
+}
+
+// DeleteURL removes the url stored under alias. Only the owner of the url
+// or an admin may remove it.
+func (s *Storage) DeleteURL(ctx context.Context, alias string, caller storage.APIKey) error {
+	res, err := s.db.ExecContext(ctx,
+		"DELETE FROM url WHERE alias=? AND (? OR key_id=?)",
+		alias, caller.Role == storage.RoleAdmin, caller.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	if ra == 0 {
+		return s.notModified(ctx, alias)
+	}
+	return nil
+}
+
+// notModified explains why a statement owner-restricted to caller touched no
+// url: either there is no such url or it belongs to someone else.
+func (s *Storage) notModified(ctx context.Context, alias string) error {
+	var exists bool
+	err := s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM url WHERE alias=?)", alias).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	if exists {
+		return fmt.Errorf("%w", storage.ErrForbidden)
+	}
+	return fmt.Errorf("%w", storage.ErrURLNotFound)
+}
+
+// GetURLInfo returns the url stored under alias with its metadata.
+// Expired urls are returned as well until the reaper removes them.
+func (s *Storage) GetURLInfo(ctx context.Context, alias string) (storage.URLInfo, error) {
+	var info storage.URLInfo
+	var expiresAt sql.NullTime
+	var ownerID sql.NullInt64
+	err := s.db.QueryRowContext(ctx,
+		"SELECT u.id, u.alias, u.url, COALESCE(u.original_url, u.url), u.created_at, u.expires_at, u.key_id, (SELECT COUNT(*) FROM clicks c WHERE c.url_id = u.id) FROM url u WHERE u.alias=?",
+		alias,
+	).Scan(&info.ID, &info.Alias, &info.URL.URL, &info.OriginalURL, &info.CreatedAt, &expiresAt, &ownerID, &info.Clicks)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.URLInfo{}, fmt.Errorf("%w", storage.ErrURLNotFound)
+		}
+		return storage.URLInfo{}, fmt.Errorf("%w", err)
+	}
+	info.ExpiresAt = expiresAt.Time
+	info.OwnerID = ownerID.Int64
+	return info, nil
+}
+
+// UpdateURL points an existing alias to newURL, keeping originalURL for
+// display. Like deletion, only the owner of the url or an admin may change it.
+func (s *Storage) UpdateURL(ctx context.Context, newURL string, originalURL string, alias string, caller storage.APIKey) error {
+	res, err := s.db.ExecContext(ctx,
+		"UPDATE url SET url=?, url_hash=?, original_url=? WHERE alias=? AND (? OR key_id=?)",
+		newURL, storage.URLHash(newURL), nullOriginal(originalURL, newURL), alias, caller.Role == storage.RoleAdmin, caller.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	if ra == 0 {
+		return s.notModified(ctx, alias)
+	}
+	return nil
+}
+
+// ListURLs returns a page of urls matching filter, ordered by id.
+func (s *Storage) ListURLs(ctx context.Context, filter storage.ListFilter) ([]storage.URL, error) {
+	conds := []string{"id > ?"}
+	args := []any{filter.After}
+	if filter.AliasPrefix != "" {
+		// LIKE is case insensitive in sqlite and needs escaping, substr is neither
+		conds = append(conds, "substr(alias, 1, length(?)) = ?")
+		args = append(args, filter.AliasPrefix, filter.AliasPrefix)
+	}
+	if filter.URLContains != "" {
+		conds = append(conds, "instr(url, ?) > 0")
+		args = append(args, filter.URLContains)
+	}
+	args = append(args, filter.Limit)
+
+	query := "SELECT id, alias, url, COALESCE(original_url, url), created_at, expires_at, key_id FROM url WHERE " +
+		strings.Join(conds, " AND ") + " ORDER BY id LIMIT ?"
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+
+	var urls []storage.URL
+	for rows.Next() {
+		var u storage.URL
+		var expiresAt sql.NullTime
+		var ownerID sql.NullInt64
+		if err := rows.Scan(&u.ID, &u.Alias, &u.URL, &u.OriginalURL, &u.CreatedAt, &expiresAt, &ownerID); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+		u.ExpiresAt = expiresAt.Time
+		u.OwnerID = ownerID.Int64
+		urls = append(urls, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return urls, nil
+}
+
+// DeleteExpiredURLs removes every url whose expiry has passed and
+// returns how many rows were removed.
+func (s *Storage) DeleteExpiredURLs(ctx context.Context) (int64, error) {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM url WHERE expires_at IS NOT NULL AND expires_at <= ?", time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+	return ra, nil
+}
+
+// FindURL returns the alias of a never expiring url saved by the api key
+// keyID, the oldest one if there are several.
+func (s *Storage) FindURL(ctx context.Context, urlToFind string, keyID int64) (string, error) {
+	var alias string
+	err := s.db.QueryRowContext(ctx,
+		"SELECT alias FROM url WHERE url_hash=? AND url=? AND key_id IS ? AND expires_at IS NULL ORDER BY id LIMIT 1",
+		storage.URLHash(urlToFind), urlToFind, nullID(keyID),
+	).Scan(&alias)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%w", storage.ErrURLNotFound)
+		}
+		return "", fmt.Errorf("%w", err)
+	}
+	return alias, nil
+}
+
+// NextAliasID returns the next value of the alias sequence.
+func (s *Storage) NextAliasID(ctx context.Context) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, "INSERT INTO alias_seq DEFAULT VALUES")
+	if err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+	// only the counter matters, the rows themselves are kept from piling up
+	if _, err := tx.ExecContext(ctx, "DELETE FROM alias_seq WHERE id < ?", id); err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+	return id, nil
+}
+
+// nullTime maps the zero time, meaning "never expires", to NULL.
+func nullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+// nullOriginal stores the original url only when it differs from the saved one.
+func nullOriginal(originalURL string, urlToSave string) sql.NullString {
+	return sql.NullString{String: originalURL, Valid: originalURL != "" && originalURL != urlToSave}
+}
+
+// nullID maps the zero id, meaning "unknown", to NULL.
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+// SaveClicks stores a batch of clicks in one transaction. Clicks on
+// aliases deleted in the meantime are silently dropped.
+func (s *Storage) SaveClicks(ctx context.Context, clicks []storage.Click) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO clicks(url_id, clicked_at, referrer, user_agent, request_id) SELECT id, ?, ?, ?, ? FROM url WHERE alias=?")
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	defer stmt.Close()
+
+	for _, c := range clicks {
+		if _, err := stmt.ExecContext(ctx, c.At.UTC(), c.Referrer, c.UserAgent, c.RequestID, c.Alias); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+	return nil
+}
+
+// GetStats returns the total number of clicks on alias and the daily
+// buckets of the last days, days without clicks are omitted.
+func (s *Storage) GetStats(ctx context.Context, alias string, days int) (storage.Stats, error) {
+	var urlID int64
+	err := s.db.QueryRowContext(ctx, "SELECT id FROM url WHERE alias=?", alias).Scan(&urlID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.Stats{}, fmt.Errorf("%w", storage.ErrURLNotFound)
+		}
+		return storage.Stats{}, fmt.Errorf("%w", err)
+	}
+
+	var stats storage.Stats
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM clicks WHERE url_id=?", urlID).Scan(&stats.Total)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("%w", err)
+	}
+
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -(days - 1))
+	rows, err := s.db.QueryContext(ctx, "SELECT substr(clicked_at, 1, 10) AS day, COUNT(*) FROM clicks WHERE url_id=? AND clicked_at >= ? GROUP BY day ORDER BY day", urlID, since)
+	if err != nil {
+		return storage.Stats{}, fmt.Errorf("%w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day storage.DailyClicks
+		if err := rows.Scan(&day.Date, &day.Clicks); err != nil {
+			return storage.Stats{}, fmt.Errorf("%w", err)
+		}
+		stats.Daily = append(stats.Daily, day)
+	}
+	if err := rows.Err(); err != nil {
+		return storage.Stats{}, fmt.Errorf("%w", err)
+	}
+
+	return stats, nil
+}
@@ -2,10 +2,84 @@ package storage
 
 import (
 	// embedded
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"time"
 )
 
 var (
 	ErrURLNotFound = errors.New("url not found")
 	ErrURlExists = errors.New("url already exists")
-)
\ No newline at end of file
+	ErrURLExpired = errors.New("url expired")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrForbidden = errors.New("url belongs to another owner")
+)
+
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// URLHash is the indexed form of a url used to find links to it.
+func URLHash(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
+
+// URL is a stored short link.
+type URL struct {
+	ID          int64
+	Alias       string
+	URL         string
+	OriginalURL string // URL as submitted, before normalization
+	CreatedAt   time.Time
+	ExpiresAt   time.Time // zero means the url never expires
+	OwnerID     int64     // id of the api key that created the url, zero if unknown
+}
+
+// URLInfo is a stored short link together with its click count.
+type URLInfo struct {
+	URL
+	Clicks int64
+}
+
+// ListFilter selects a page of urls ordered by id. After is the id of the
+// last url of the previous page, 0 for the first page.
+type ListFilter struct {
+	AliasPrefix string
+	URLContains string
+	After       int64
+	Limit       int
+}
+
+// APIKey identifies a client of the management api. Only the hash of the
+// key is stored, the key itself is shown once on creation.
+type APIKey struct {
+	ID        int64
+	Name      string
+	Role      string
+	CreatedAt time.Time
+	RevokedAt time.Time // zero while the key is active
+}
+
+// Click is a single resolved redirect.
+type Click struct {
+	Alias     string
+	At        time.Time
+	Referrer  string
+	UserAgent string
+	RequestID string
+}
+
+// Stats is the visit summary of a single alias.
+type Stats struct {
+	Total int64
+	Daily []DailyClicks
+}
+
+// DailyClicks is the number of clicks during one UTC day.
+type DailyClicks struct {
+	Date   string `json:"date"` // YYYY-MM-DD
+	Clicks int64  `json:"clicks"`
+}
@@ -2,38 +2,42 @@ package tests
 
 import (
 	// project
-	"go-url-shortener/internal/config"
+	keyCreate "go-url-shortener/internal/http-server/handlers/apikey/create"
 	"go-url-shortener/internal/http-server/handlers/delete"
+	"go-url-shortener/internal/http-server/handlers/info"
 	"go-url-shortener/internal/http-server/handlers/redirect"
 	"go-url-shortener/internal/http-server/handlers/save"
+	"go-url-shortener/internal/http-server/handlers/stats"
+	"go-url-shortener/internal/http-server/middleware/auth"
 	"go-url-shortener/internal/http-server/middleware/logger"
+	"go-url-shortener/internal/lib/alias"
+	"go-url-shortener/internal/lib/api"
 	"go-url-shortener/internal/lib/logger/handlers/slogpretty"
-	"go-url-shortener/internal/storage/postgres"
+	"go-url-shortener/internal/storage/clicks"
+	"go-url-shortener/internal/storage/memory"
 
 	// embedded
 	"bytes"
-	"encoding/base64"
 	"encoding/json"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	// external
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
 
-func TestURLShortener_FullCRUD(t *testing.T) {
-	cfg := config.MustLoad()
+const testAdminKey = "us_test-admin-key"
 
+func TestURLShortener_FullCRUD(t *testing.T) {
 	log := setupPrettySlog()
 
-	st, err := postgres.NewStorage(cfg.Postgres)
-	if err != nil {
-		t.Fatalf("postgres init failed: %v", err)
-	}
+	st := memory.NewStorage()
+	recorder := clicks.NewRecorder(log, st, 16, 16, time.Hour)
 
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
@@ -41,24 +45,41 @@ func TestURLShortener_FullCRUD(t *testing.T) {
 	r.Use(middleware.Recoverer)
 
 	r.Route("/url", func(r chi.Router) {
-		r.Use(middleware.BasicAuth(
-			"url-shortener",
-			map[string]string{
-				cfg.HttpServer.User: cfg.HttpServer.Password,
-			},
-		))
-		r.Post("/", save.New(log, st))
+		r.Use(auth.New(log, st, testAdminKey))
+		r.Post("/", save.New(log, st, save.Options{
+			Generator:       alias.NewCounter(st, 0xC0FFEE),
+			MaxCustomLength: 32,
+		}))
+		r.Get("/{alias}", info.New(log, st))
 		r.Delete("/{alias}", delete.New(log, st))
+		r.Get("/{alias}/stats", stats.New(log, st))
+	})
+	r.Route("/admin/keys", func(r chi.Router) {
+		r.Use(auth.New(log, st, testAdminKey))
+		r.Use(auth.RequireAdmin)
+		r.Post("/", keyCreate.New(log, st))
 	})
 
-	r.Get("/{alias}", redirect.New(log, st))
+	r.Get("/{alias}", redirect.New(log, st, recorder))
 
 	server := httptest.NewServer(r)
 	defer server.Close()
 
-	auth := "Basic " + base64.StdEncoding.EncodeToString(
-		[]byte(cfg.HttpServer.User+":"+cfg.HttpServer.Password),
-	)
+	// the bootstrap admin key issues regular keys for the rest of the flow
+	authorization := "Bearer " + createKey(t, server.URL, "owner")
+	otherAuthorization := "Bearer " + createKey(t, server.URL, "other")
+
+	// a regular key can't manage keys
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/admin/keys/", bytes.NewReader([]byte(`{"name":"other"}`)))
+	req.Header.Set("Authorization", authorization)
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("create key request failed: %v", err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", res.StatusCode)
+	}
 
 	saveBody := map[string]string{
 		"url": "https://example.com/test",
@@ -66,11 +87,11 @@ func TestURLShortener_FullCRUD(t *testing.T) {
 
 	body, _ := json.Marshal(saveBody)
 
-	req, _ := http.NewRequest(http.MethodPost, server.URL+"/url/", bytes.NewReader(body))
-	req.Header.Set("Authorization", auth)
+	req, _ = http.NewRequest(http.MethodPost, server.URL+"/url/", bytes.NewReader(body))
+	req.Header.Set("Authorization", authorization)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := http.DefaultClient.Do(req)
+	res, err = http.DefaultClient.Do(req)
 	if err != nil {
 		t.Fatalf("save request failed: %v", err)
 	}
@@ -91,6 +112,14 @@ func TestURLShortener_FullCRUD(t *testing.T) {
 
 	alias := saveResp.Alias
 
+	urlInfo, err := api.GetInfo(server.URL+"/url/"+alias, authorization)
+	if err != nil {
+		t.Fatalf("info request failed: %v", err)
+	}
+	if urlInfo.URL != "https://example.com/test" {
+		t.Fatalf("unexpected info url: %s", urlInfo.URL)
+	}
+
 	req, _ = http.NewRequest(http.MethodGet, server.URL+"/"+alias, nil)
 	client := &http.Client{
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
@@ -113,8 +142,41 @@ func TestURLShortener_FullCRUD(t *testing.T) {
 		t.Fatalf("unexpected redirect location: %s", loc)
 	}
 
+	// flush the recorded click before asking for stats
+	recorder.Close()
+
+	req, _ = http.NewRequest(http.MethodGet, server.URL+"/url/"+alias+"/stats", nil)
+	req.Header.Set("Authorization", authorization)
+
+	res, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("stats request failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	var statsResp stats.Response
+	_ = json.NewDecoder(res.Body).Decode(&statsResp)
+
+	if statsResp.Total != 1 || len(statsResp.Daily) != 1 {
+		t.Fatalf("expected 1 click, got %+v", statsResp)
+	}
+
+	// only the owner may delete the link
+	req, _ = http.NewRequest(http.MethodDelete, server.URL+"/url/"+alias, nil)
+	req.Header.Set("Authorization", otherAuthorization)
+
+	res, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("delete request failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", res.StatusCode)
+	}
+
 	req, _ = http.NewRequest(http.MethodDelete, server.URL+"/url/"+alias, nil)
-	req.Header.Set("Authorization", auth)
+	req.Header.Set("Authorization", authorization)
 
 	res, err = http.DefaultClient.Do(req)
 	if err != nil {
@@ -133,8 +195,8 @@ func TestURLShortener_FullCRUD(t *testing.T) {
 	}
 	defer res.Body.Close()
 
-	if res.StatusCode != http.StatusOK {
-		t.Fatalf("expected 200 with error body, got %d", res.StatusCode)
+	if res.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", res.StatusCode)
 	}
 
 	var errResp map[string]string
@@ -145,6 +207,30 @@ func TestURLShortener_FullCRUD(t *testing.T) {
 	}
 }
 
+// createKey issues a regular api key with the bootstrap admin key.
+func createKey(t *testing.T, serverURL string, name string) string {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]string{"name": name})
+	req, _ := http.NewRequest(http.MethodPost, serverURL+"/admin/keys/", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testAdminKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("create key request failed: %v", err)
+	}
+	defer res.Body.Close()
+
+	var keyResp keyCreate.Response
+	_ = json.NewDecoder(res.Body).Decode(&keyResp)
+	if res.StatusCode != http.StatusOK || keyResp.Key == "" {
+		t.Fatalf("expected 200 with a key, got %d", res.StatusCode)
+	}
+
+	return keyResp.Key
+}
+
 func setupPrettySlog() *slog.Logger {
 	opts := slogpretty.PrettyHandlerOptions{
 		SlogOpts: &slog.HandlerOptions{